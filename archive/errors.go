@@ -0,0 +1,8 @@
+package archive
+
+import "errors"
+
+var (
+	UnsupportedVersionErr = errors.New("archive version is not supported")
+	InvalidArchiveErr     = errors.New("archive contains invalid data")
+)
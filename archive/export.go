@@ -0,0 +1,42 @@
+package archive
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Export collects all data of the user into a single [Archive].
+func Export(userId uuid.UUID) (*Archive, error) {
+	user, err := users.GetById(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	platformList, err := platforms.GetPlatforms(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	gameList, err := games.GetAllGames(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	playthroughList, err := playthroughs.GetPlaythroughs(uuid.Nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archive{
+		Version:      Version,
+		ExportedAt:   time.Now(),
+		User:         user,
+		Platforms:    platformList,
+		Games:        gameList,
+		Playthroughs: playthroughList,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package archive
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	t.Run("All user data exported", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := platforms.Platform{Name: "platform", ShortName: "pl"}
+		tests.PanicOnErr(platforms.CreatePlatform(&platform, userId))
+		game := games.Game{PlatformId: platform.Id, Title: "game", Released: true}
+		tests.PanicOnErr(games.CreateGame(&game, userId))
+		playthrough := playthroughs.Playthrough{GameId: game.Id, StartDate: tests.GetRandomTestTime()}
+		tests.PanicOnErr(playthroughs.CreatePlaythrough(&playthrough, userId))
+		otherUserId := tests.MakeTestUserId(db)
+		tests.PanicOnErr(platforms.CreatePlatform(&platforms.Platform{Name: "other", ShortName: "ot"}, otherUserId))
+
+		result, err := Export(userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Version, result.Version)
+		assert.Equal(t, userId, result.User.Id)
+		assert.Len(t, result.Platforms, 1)
+		assert.Len(t, result.Games, 1)
+		assert.Len(t, result.Playthroughs, 1)
+		assert.Equal(t, game.Id, result.Playthroughs[0].GameId)
+	})
+}
@@ -0,0 +1,49 @@
+package archive
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Version is the version of the archive format created by Export.
+// It must be increased whenever a change to the format would prevent older archives from being read.
+const Version = 1
+
+// Archive holds the complete library of a single user.
+type Archive struct {
+	Version      int
+	ExportedAt   time.Time
+	User         *users.User
+	Platforms    []*platforms.Platform
+	Games        []*games.Game
+	Playthroughs []*playthroughs.Playthrough
+}
+
+// Conflict describes a single item from the [Archive] that could not be restored as-is.
+type Conflict struct {
+	Entity   string
+	SourceId uuid.UUID
+	Message  string
+}
+
+// RestoreResult summarises the changes made by Restore.
+type RestoreResult struct {
+	PlatformsCreated    int
+	PlatformsMerged     int
+	GamesCreated        int
+	PlaythroughsCreated int
+	Conflicts           []*Conflict
+}
+
+func (r *RestoreResult) addConflict(entity string, sourceId uuid.UUID, format string, args ...any) {
+	r.Conflicts = append(r.Conflicts, &Conflict{
+		Entity:   entity,
+		SourceId: sourceId,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
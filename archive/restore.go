@@ -0,0 +1,156 @@
+package archive
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"unicode/utf8"
+)
+
+const (
+	archiveEntity     = "archive"
+	platformEntity    = "platform"
+	gameEntity        = "game"
+	playthroughEntity = "playthrough"
+)
+
+// Restore recreates the contents of the [Archive] for the user in a single transaction.
+// All items are created with new ids, while relations between them are preserved.
+//
+// Platforms with the same name or short name as an existing platform of the user are not created.
+// Instead, games from the archive are assigned to the existing platform, and a [Conflict] is reported.
+//
+// If the archive contains invalid data, nothing is created and InvalidArchiveErr is returned along with the conflicts found.
+func Restore(archive *Archive, userId uuid.UUID) (*RestoreResult, error) {
+	result := &RestoreResult{Conflicts: make([]*Conflict, 0)}
+
+	if archive.Version < 1 || archive.Version > Version {
+		result.addConflict(archiveEntity, uuid.Nil, "archive version %d is not supported, the latest supported version is %d", archive.Version, Version)
+		return result, UnsupportedVersionErr
+	}
+	if !validate(archive, result) {
+		return result, InvalidArchiveErr
+	}
+
+	err := utils.RunInTransaction(func(tx gotabase.Connector) error {
+		platformIds, err := restorePlatforms(tx, archive.Platforms, userId, result)
+		if err != nil {
+			return err
+		}
+
+		gameIds := make(map[uuid.UUID]uuid.UUID, len(archive.Games))
+		for _, game := range archive.Games {
+			sourceId := game.Id
+			game.PlatformId = platformIds[game.PlatformId]
+			if err = games.CreateGameTx(tx, game, userId); err != nil {
+				return err
+			}
+			gameIds[sourceId] = game.Id
+			result.GamesCreated++
+		}
+
+		for _, playthrough := range archive.Playthroughs {
+			playthrough.GameId = gameIds[playthrough.GameId]
+			if err = playthroughs.CreatePlaythroughTx(tx, playthrough, userId); err != nil {
+				return err
+			}
+			result.PlaythroughsCreated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// restorePlatforms creates platforms from the archive and returns a map of archive ids to ids of platforms of the user.
+func restorePlatforms(tx gotabase.Connector, archived []*platforms.Platform, userId uuid.UUID, result *RestoreResult) (map[uuid.UUID]uuid.UUID, error) {
+	existing, err := platforms.GetPlatformsTx(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID, len(archived))
+	for _, platform := range archived {
+		if match := findMatchingPlatform(existing, platform); match != nil {
+			ids[platform.Id] = match.Id
+			result.PlatformsMerged++
+			result.addConflict(platformEntity, platform.Id, "platform %s (%s) already exists, games were assigned to %s (%s)", platform.Name, platform.ShortName, match.Name, match.ShortName)
+			continue
+		}
+
+		sourceId := platform.Id
+		if err = platforms.CreatePlatformTx(tx, platform, userId); err != nil {
+			return nil, err
+		}
+		ids[sourceId] = platform.Id
+		existing = append(existing, platform)
+		result.PlatformsCreated++
+	}
+
+	return ids, nil
+}
+
+func findMatchingPlatform(existing []*platforms.Platform, platform *platforms.Platform) *platforms.Platform {
+	var shortNameMatch *platforms.Platform
+	for _, candidate := range existing {
+		if candidate.Name == platform.Name {
+			return candidate
+		}
+		if shortNameMatch == nil && candidate.ShortName == platform.ShortName {
+			shortNameMatch = candidate
+		}
+	}
+	return shortNameMatch
+}
+
+// validate checks the archive for data that could not be restored and reports it as conflicts.
+func validate(archive *Archive, result *RestoreResult) bool {
+	platformIds := make(map[uuid.UUID]bool, len(archive.Platforms))
+	for _, platform := range archive.Platforms {
+		if platformIds[platform.Id] {
+			result.addConflict(platformEntity, platform.Id, "platform id is duplicated")
+		}
+		platformIds[platform.Id] = true
+		if platform.Name == "" || utf8.RuneCountInString(platform.Name) > 200 {
+			result.addConflict(platformEntity, platform.Id, "platform name must be between 1 and 200 characters long")
+		}
+		if platform.ShortName == "" || utf8.RuneCountInString(platform.ShortName) > 5 {
+			result.addConflict(platformEntity, platform.Id, "platform short name must be between 1 and 5 characters long")
+		}
+	}
+
+	gameIds := make(map[uuid.UUID]bool, len(archive.Games))
+	for _, game := range archive.Games {
+		if gameIds[game.Id] {
+			result.addConflict(gameEntity, game.Id, "game id is duplicated")
+		}
+		gameIds[game.Id] = true
+		if game.Title == "" || utf8.RuneCountInString(game.Title) > 500 {
+			result.addConflict(gameEntity, game.Id, "game title must be between 1 and 500 characters long")
+		}
+		if !platformIds[game.PlatformId] {
+			result.addConflict(gameEntity, game.Id, "game references platform %s, which is not in the archive", game.PlatformId)
+		}
+	}
+
+	for _, playthrough := range archive.Playthroughs {
+		if !gameIds[playthrough.GameId] {
+			result.addConflict(playthroughEntity, playthrough.Id, "playthrough references game %s, which is not in the archive", playthrough.GameId)
+		}
+		if playthrough.Status < playthroughs.PlaythroughInProgress || playthrough.Status > playthroughs.PlaythroughSuspended {
+			result.addConflict(playthroughEntity, playthrough.Id, "playthrough status %d is not valid", playthrough.Status)
+		}
+		if playthrough.Runtime.Valid && playthrough.Runtime.Int32 < 0 {
+			result.addConflict(playthroughEntity, playthrough.Id, "playthrough runtime cannot be negative")
+		}
+	}
+
+	return len(result.Conflicts) == 0
+}
@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func makeTestArchive() *Archive {
+	platformId := tests.GetRandomUuid()
+	gameId := tests.GetRandomUuid()
+	return &Archive{
+		Version: Version,
+		Platforms: []*platforms.Platform{
+			{Id: platformId, Name: "archived platform", ShortName: "ap"},
+		},
+		Games: []*games.Game{
+			{Id: gameId, PlatformId: platformId, Title: "archived game", Released: true},
+		},
+		Playthroughs: []*playthroughs.Playthrough{
+			{Id: tests.GetRandomUuid(), GameId: gameId, StartDate: tests.GetRandomTestTime(), Status: playthroughs.PlaythroughCompleted},
+		},
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("Archive restored with new ids", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		archive := makeTestArchive()
+		sourceGameId := archive.Games[0].Id
+
+		result, err := Restore(archive, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.PlatformsCreated)
+		assert.Equal(t, 1, result.GamesCreated)
+		assert.Equal(t, 1, result.PlaythroughsCreated)
+		assert.Empty(t, result.Conflicts)
+		list, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, list, 1)
+		assert.NotEqual(t, sourceGameId, list[0].Id)
+		playthroughList, err := playthroughs.GetPlaythroughs(list[0].Id, userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, playthroughList, 1)
+	})
+
+	t.Run("Existing platform reused and reported", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		existing := platforms.Platform{Name: "archived platform", ShortName: "ex"}
+		tests.PanicOnErr(platforms.CreatePlatform(&existing, userId))
+		archive := makeTestArchive()
+
+		result, err := Restore(archive, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.PlatformsCreated)
+		assert.Equal(t, 1, result.PlatformsMerged)
+		assert.Len(t, result.Conflicts, 1)
+		list, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, existing.Id, list[0].PlatformId)
+	})
+
+	t.Run("Invalid references - nothing restored", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		archive := makeTestArchive()
+		archive.Games[0].PlatformId = uuid.Nil
+
+		result, err := Restore(archive, userId)
+
+		assert.Equal(t, InvalidArchiveErr, err)
+		assert.Len(t, result.Conflicts, 1)
+		list, err := platforms.GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		archive := makeTestArchive()
+		archive.Version = Version + 1
+
+		_, err := Restore(archive, tests.GetRandomUuid())
+
+		assert.Equal(t, UnsupportedVersionErr, err)
+	})
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EntityType string
+
+const (
+	EntityGame        EntityType = "game"
+	EntityPlatform    EntityType = "platform"
+	EntityPlaythrough EntityType = "playthrough"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	// ActionDelete is used for entities moved to the trash, as well as ones removed right away.
+	ActionDelete Action = "delete"
+	// ActionRestore is used for entities restored from the trash.
+	ActionRestore Action = "restore"
+	// ActionPurge is used for entities removed for good, after being in the trash.
+	ActionPurge Action = "purge"
+)
+
+// Entry is a single change recorded in the audit log.
+// Entries are written by the database itself, in the same transaction as the change, so they cannot be created from here.
+type Entry struct {
+	Id         int64
+	EntityType EntityType
+	EntityId   uuid.UUID
+	Action     Action
+	// Before and After are JSON objects with the row of the entity as stored in the database.
+	// Before is nil for created entities, and After is nil for removed ones.
+	Before    []byte
+	After     []byte
+	ChangedAt time.Time
+}
+
+// Filter limits the entries returned to the ones matching all of the set criteria.
+// Empty and nil values are ignored.
+type Filter struct {
+	EntityType EntityType
+	EntityId   uuid.NullUUID
+	// From and To limit the entries to changes made within the half-open range.
+	From *time.Time
+	To   *time.Time
+}
+
+func scanEntry(row gotabase.Row) (*Entry, error) {
+	var entry Entry
+	if err := row.Scan(&entry.Id, &entry.EntityType, &entry.EntityId, &entry.Action, &entry.Before, &entry.After, &entry.ChangedAt); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
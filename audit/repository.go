@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// GetEntries returns the changes made to the library of the user matching the filter, from the most recent ones, with offset and limit used for pagination.
+func GetEntries(offset int, limit int, filter Filter, userId uuid.UUID) ([]*Entry, error) {
+	condition, args := filter.condition([]interface{}{offset, limit, userId})
+	query := `select id, entity_type, entity_id, action, before, after, changed_at from audit_log where user_id = $3` + condition + ` order by id desc offset $1 limit $2`
+	return operations.QueryRows(getDatabase(), scanEntry, query, args...)
+}
+
+// condition returns an SQL condition matching the filter, to be appended to the where clause of a query on the audit log.
+// Values are appended to args as query parameters, and the extended list is returned along with the condition.
+func (f Filter) condition(args []interface{}) (string, []interface{}) {
+	var condition strings.Builder
+
+	if f.EntityType != "" {
+		args = append(args, f.EntityType)
+		condition.WriteString(fmt.Sprintf(" and entity_type = $%d", len(args)))
+	}
+
+	if f.EntityId.Valid {
+		args = append(args, f.EntityId.UUID)
+		condition.WriteString(fmt.Sprintf(" and entity_id = $%d", len(args)))
+	}
+
+	if f.From != nil {
+		args = append(args, *f.From)
+		condition.WriteString(fmt.Sprintf(" and changed_at >= $%d", len(args)))
+	}
+
+	if f.To != nil {
+		args = append(args, *f.To)
+		condition.WriteString(fmt.Sprintf(" and changed_at < $%d", len(args)))
+	}
+
+	return condition.String(), args
+}
@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"encoding/json"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makeGame(userId uuid.UUID) (*platforms.Platform, *games.Game) {
+	platform := &platforms.Platform{Name: "test", ShortName: "tst"}
+	tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+	game := &games.Game{PlatformId: platform.Id, Title: "test game", Released: true}
+	tests.PanicOnErr(games.CreateGame(game, userId))
+	return platform, game
+}
+
+func TestFilterCondition(t *testing.T) {
+	t.Run("Empty filter", func(t *testing.T) {
+		condition, args := Filter{}.condition([]interface{}{1})
+
+		assert.Empty(t, condition)
+		assert.Equal(t, []interface{}{1}, args)
+	})
+
+	t.Run("Parameters numbered after existing args", func(t *testing.T) {
+		id := tests.GetRandomUuid()
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		filter := Filter{EntityType: EntityGame, EntityId: uuid.NullUUID{UUID: id, Valid: true}, From: &from}
+
+		condition, args := filter.condition([]interface{}{1, 2})
+
+		assert.Equal(t, []interface{}{1, 2, EntityGame, id, from}, args)
+		assert.Equal(t, " and entity_type = $3 and entity_id = $4 and changed_at >= $5", condition)
+	})
+}
+
+func TestGetEntries(t *testing.T) {
+	t.Run("Changes recorded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		game.Title = "new title"
+		tests.PanicOnErr(games.UpdateGame(game, userId))
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+
+		entries, err := GetEntries(0, 10, Filter{EntityType: EntityGame}, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 3)
+		assert.Equal(t, []Action{ActionDelete, ActionUpdate, ActionCreate}, []Action{entries[0].Action, entries[1].Action, entries[2].Action})
+		for _, entry := range entries {
+			assert.Equal(t, game.Id, entry.EntityId)
+		}
+		var before, after map[string]any
+		assert.NoError(t, json.Unmarshal(entries[1].Before, &before))
+		assert.NoError(t, json.Unmarshal(entries[1].After, &after))
+		assert.Equal(t, "test game", before["title"])
+		assert.Equal(t, "new title", after["title"])
+		assert.NoError(t, json.Unmarshal(entries[0].After, &after))
+		assert.NotNil(t, after["deleted_at"])
+		assert.Nil(t, entries[2].Before)
+	})
+
+	t.Run("Unchanged update not recorded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		tests.PanicOnErr(games.UpdateGame(game, userId))
+
+		entries, err := GetEntries(0, 10, Filter{EntityType: EntityGame}, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Playthroughs purged with their game recorded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		playthrough := &playthroughs.Playthrough{GameId: game.Id, StartDate: tests.GetRandomTestTime()}
+		tests.PanicOnErr(playthroughs.CreatePlaythrough(playthrough, userId))
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+
+		_, err := getDatabase().Exec(`delete from games where id = $1`, game.Id)
+		tests.PanicOnErr(err)
+
+		entries, err := GetEntries(0, 10, Filter{}, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, ActionDelete, entries[0].Action)
+		assert.Equal(t, EntityPlaythrough, entries[0].EntityType)
+		assert.Nil(t, entries[0].After)
+		assert.Equal(t, ActionPurge, entries[1].Action)
+		assert.Equal(t, game.Id, entries[1].EntityId)
+	})
+
+	t.Run("Changes of another user not returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		makeGame(tests.MakeTestUserId(getDatabase()))
+
+		entries, err := GetEntries(0, 10, Filter{}, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Filtered by date", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform, _ := makeGame(userId)
+		from := time.Now().Add(time.Hour)
+
+		entries, err := GetEntries(0, 10, Filter{From: &from}, userId)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+
+		entries, err = GetEntries(0, 10, Filter{EntityType: EntityPlatform}, userId)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, platform.Id, entries[0].EntityId)
+	})
+}
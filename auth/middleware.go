@@ -1,29 +1,55 @@
 package auth
 
 import (
+	"github.com/KowalskiPiotr98/ludivault/tokens"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 )
 
+const bearerPrefix = "Bearer "
+
 // GetUserMiddleware returns gin handler function that reads user data from session store and saves it as context item.
+// Requests with an Authorization: Bearer header are authenticated with the API token instead of the session cookie.
 // Note that this middleware does not prevent not logged in users from using the app.
 func GetUserMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userId, err := RetrieveUserFromSession(c)
+		if header := c.GetHeader("Authorization"); header != "" {
+			if !strings.HasPrefix(header, bearerPrefix) {
+				log.Debugf("Unsupported authorization header scheme")
+				return
+			}
+
+			token, err := tokens.GetTokenBySecret(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+			if err != nil {
+				log.Debugf("Failed to retrieve user from API token: %v", err)
+				return
+			}
+
+			c.Set("userId", token.UserId)
+			c.Set("apiToken", &token.Token)
+
+			c.Next()
+			return
+		}
+
+		session, err := retrieveSession(c)
 		if err != nil {
 			log.Debugf("Failed to retrieve user from session. Possibly just not logged in: %v", err)
 			return
 		}
 
-		c.Set("userId", userId)
+		c.Set("userId", session.UserId)
+		c.Set("sessionId", session.Id)
 
 		c.Next()
 	}
 }
 
 // GetLoginRequiredMiddleware will return 401 Unauthorised when the user is not logged in.
+// Users authenticated with a read only API token will receive 403 Forbidden for any request that could modify data.
 // Note that this middleware must be registered after the GetUserMiddleware.
 func GetLoginRequiredMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -32,6 +58,25 @@ func GetLoginRequiredMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if token := getApiToken(c); token != nil && !token.AllowsWrites() && !isSafeMethod(c.Request.Method) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetSessionRequiredMiddleware will return 401 Unauthorised when the user is not logged in with a session cookie.
+// This is used to prevent API tokens from managing user credentials.
+// Note that this middleware must be registered after the GetUserMiddleware.
+func GetSessionRequiredMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsLoggedIn(c) || getApiToken(c) != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -54,3 +99,25 @@ func GetUserId(c *gin.Context) uuid.UUID {
 	}
 	return value.(uuid.UUID)
 }
+
+// GetSessionId returns the id of the server-side session used by the current user.
+// It will return uuid.Nil if no user is logged in or the user is authenticated with an API token.
+func GetSessionId(c *gin.Context) uuid.UUID {
+	value, exists := c.Get("sessionId")
+	if !exists {
+		return uuid.Nil
+	}
+	return value.(uuid.UUID)
+}
+
+func getApiToken(c *gin.Context) *tokens.Token {
+	value, exists := c.Get("apiToken")
+	if !exists {
+		return nil
+	}
+	return value.(*tokens.Token)
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	userSessions "github.com/KowalskiPiotr98/ludivault/sessions"
 	"github.com/KowalskiPiotr98/ludivault/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -8,18 +11,21 @@ import (
 	"github.com/markbates/goth/gothic"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"time"
 )
 
-//todo: storing sessions in cookie only is bad as it prevents any form of server-side tracking of sessions
-// but it'll have to do for now
+const (
+	sessionMaxAge = 86400 * 30
+	// sessionTouchInterval limits how often the last seen time of a session is written to the database
+	sessionTouchInterval = time.Minute
+)
 
 var (
 	UserSessionName = "ludivault-session"
 	SetupSession    = func(baseDomain string) sessions.Store {
 		key := utils.GetRequiredConfig("SESSION_KEY")
-		maxAge := 86400 * 30
 		store := sessions.NewCookieStore([]byte(key))
-		store.MaxAge(maxAge)
+		store.MaxAge(sessionMaxAge)
 		store.Options.Path = "/"
 		store.Options.HttpOnly = true
 		store.Options.Secure = gin.Mode() != gin.DebugMode
@@ -39,7 +45,7 @@ func InitSessionStore(baseDomain string) {
 	gothic.Store = authStore
 }
 
-// StoreUserInSession adds the user data to session store.
+// StoreUserInSession creates a new server-side session for the user and stores its id in the session cookie.
 func StoreUserInSession(c *gin.Context, userId uuid.UUID) error {
 	ensureSessionStoreInit()
 
@@ -49,7 +55,13 @@ func StoreUserInSession(c *gin.Context, userId uuid.UUID) error {
 		return err
 	}
 
-	session.Values["userId"] = userId
+	userSession := userSessions.NewSession(userId, sessionMaxAge*time.Second, c.ClientIP(), c.Request.UserAgent())
+	if err = userSessions.CreateSession(userSession); err != nil {
+		log.Warnf("Failed to create session: %v", err)
+		return err
+	}
+
+	session.Values["sessionId"] = userSession.Id
 
 	if err = session.Save(c.Request, c.Writer); err != nil {
 		log.Warnf("Failed to save session: %v", err)
@@ -60,20 +72,13 @@ func StoreUserInSession(c *gin.Context, userId uuid.UUID) error {
 }
 
 // RetrieveUserFromSession attempts to get user data from session store.
+// Sessions that were revoked or have expired are rejected.
 func RetrieveUserFromSession(c *gin.Context) (uuid.UUID, error) {
-	ensureSessionStoreInit()
-
-	session, err := authStore.Get(c.Request, UserSessionName)
+	userSession, err := retrieveSession(c)
 	if err != nil {
-		log.Warnf("Failed to get session: %v", err)
 		return uuid.Nil, err
 	}
-
-	id, ok := session.Values["userId"].(uuid.UUID)
-	if !ok {
-		return uuid.Nil, UserIdNotStored
-	}
-	return id, nil
+	return userSession.UserId, nil
 }
 
 func RemoveUserSession(c *gin.Context) error {
@@ -85,6 +90,12 @@ func RemoveUserSession(c *gin.Context) error {
 		return err
 	}
 
+	if id, ok := session.Values["sessionId"].(uuid.UUID); ok && IsLoggedIn(c) {
+		if err = userSessions.RevokeSession(id, GetUserId(c)); err != nil && !errors.Is(err, operations.Errors.DataNotFoundErr) {
+			log.Warnf("Failed to revoke session: %v", err)
+		}
+	}
+
 	// remove what's left of the session
 	session.Options.MaxAge = -1
 	if err := session.Save(c.Request, c.Writer); err != nil {
@@ -95,8 +106,84 @@ func RemoveUserSession(c *gin.Context) error {
 	return nil
 }
 
+func retrieveSession(c *gin.Context) (*userSessions.Session, error) {
+	ensureSessionStoreInit()
+
+	session, err := authStore.Get(c.Request, UserSessionName)
+	if err != nil {
+		log.Warnf("Failed to get session: %v", err)
+		return nil, err
+	}
+
+	id, ok := session.Values["sessionId"].(uuid.UUID)
+	if !ok {
+		return nil, SessionIdNotStored
+	}
+
+	userSession, err := userSessions.GetActiveSession(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Since(userSession.LastSeenAt) > sessionTouchInterval {
+		userSession.Refresh(c.ClientIP(), c.Request.UserAgent())
+		if err = userSessions.TouchSession(userSession); err != nil {
+			log.Warnf("Failed to update session last seen time: %v", err)
+		}
+	}
+
+	return userSession, nil
+}
+
 func ensureSessionStoreInit() {
 	if authStore == nil {
 		log.Panic("Session store is not initialized")
 	}
 }
+
+// MarkIdentityLinking stores a flag in the session cookie, indicating that the next completed login should link
+// the identity to the currently logged-in user instead of starting a new session.
+func MarkIdentityLinking(c *gin.Context) error {
+	return setLinkingFlag(c, true)
+}
+
+// ConsumeIdentityLinking returns true if MarkIdentityLinking was called for the session before.
+// The flag is cleared, so that only a single login is affected.
+func ConsumeIdentityLinking(c *gin.Context) (bool, error) {
+	ensureSessionStoreInit()
+
+	session, err := authStore.Get(c.Request, UserSessionName)
+	if err != nil {
+		log.Warnf("Failed to get session: %v", err)
+		return false, err
+	}
+
+	linking, _ := session.Values["linkIdentity"].(bool)
+	if !linking {
+		return false, nil
+	}
+	return true, setLinkingFlag(c, false)
+}
+
+func setLinkingFlag(c *gin.Context, value bool) error {
+	ensureSessionStoreInit()
+
+	session, err := authStore.Get(c.Request, UserSessionName)
+	if err != nil {
+		log.Warnf("Failed to get session: %v", err)
+		return err
+	}
+
+	if value {
+		session.Values["linkIdentity"] = true
+	} else {
+		delete(session.Values, "linkIdentity")
+	}
+
+	if err = session.Save(c.Request, c.Writer); err != nil {
+		log.Warnf("Failed to save session: %v", err)
+		return err
+	}
+
+	return nil
+}
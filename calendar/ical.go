@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateFormat      = "20060102"
+	timestampFormat = "20060102T150405Z"
+
+	// maxLineLength is the limit of octets in a single line, after which lines are folded.
+	maxLineLength = 75
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// WriteCalendar writes the events to w as an iCalendar (RFC 5545) object, with each event lasting a whole day.
+// Dates of the events are taken in UTC, and now is used as the time stamp of the events.
+func WriteCalendar(w io.Writer, events []*Event, now time.Time) error {
+	writer := bufio.NewWriter(w)
+	stamp := now.UTC().Format(timestampFormat)
+
+	writeLine(writer, "BEGIN:VCALENDAR")
+	writeLine(writer, "VERSION:2.0")
+	writeLine(writer, "PRODID:-//Ludivault//Ludivault//EN")
+	writeLine(writer, "CALSCALE:GREGORIAN")
+	writeLine(writer, "METHOD:PUBLISH")
+	writeLine(writer, "X-WR-CALNAME:Ludivault")
+	for _, event := range events {
+		date := event.Date.UTC()
+		writeLine(writer, "BEGIN:VEVENT")
+		writeLine(writer, "UID:"+escapeText(event.Uid))
+		writeLine(writer, "DTSTAMP:"+stamp)
+		writeLine(writer, "DTSTART;VALUE=DATE:"+date.Format(dateFormat))
+		writeLine(writer, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format(dateFormat))
+		writeLine(writer, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(writer, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(writer, "TRANSP:TRANSPARENT")
+		writeLine(writer, "END:VEVENT")
+	}
+	writeLine(writer, "END:VCALENDAR")
+
+	return writer.Flush()
+}
+
+// escapeText escapes the characters with special meaning in TEXT values.
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// writeLine writes the content line terminated with CRLF, folding it into several lines if it is too long.
+// Lines are only split between characters, so that multi-byte characters are kept intact.
+// Errors are returned by the final flush of the writer.
+func writeLine(writer *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		split := limit
+		for split > 0 && !utf8.RuneStart(line[split]) {
+			split--
+		}
+		writer.WriteString(line[:split])
+		writer.WriteString("\r\n ")
+		line = line[split:]
+		// continuation lines start with a space, which counts towards the limit
+		limit = maxLineLength - 1
+	}
+	writer.WriteString(line)
+	writer.WriteString("\r\n")
+}
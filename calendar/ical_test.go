@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("All-day events written", func(t *testing.T) {
+		events := []*Event{
+			{Uid: "1-release@ludivault", Date: time.Date(2026, 8, 20, 0, 0, 0, 0, time.UTC), Summary: "Hades II release", Description: "Platform: PC"},
+		}
+		var buffer bytes.Buffer
+
+		err := WriteCalendar(&buffer, events, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+			"VERSION:2.0\r\n"+
+			"PRODID:-//Ludivault//Ludivault//EN\r\n"+
+			"CALSCALE:GREGORIAN\r\n"+
+			"METHOD:PUBLISH\r\n"+
+			"X-WR-CALNAME:Ludivault\r\n"+
+			"BEGIN:VEVENT\r\n"+
+			"UID:1-release@ludivault\r\n"+
+			"DTSTAMP:20260301T123000Z\r\n"+
+			"DTSTART;VALUE=DATE:20260820\r\n"+
+			"DTEND;VALUE=DATE:20260821\r\n"+
+			"SUMMARY:Hades II release\r\n"+
+			"DESCRIPTION:Platform: PC\r\n"+
+			"TRANSP:TRANSPARENT\r\n"+
+			"END:VEVENT\r\n"+
+			"END:VCALENDAR\r\n", buffer.String())
+	})
+
+	t.Run("Dates taken in UTC", func(t *testing.T) {
+		events := []*Event{{Uid: "1", Date: time.Date(2026, 1, 1, 0, 30, 0, 0, time.FixedZone("test", 60*60)), Summary: "test"}}
+		var buffer bytes.Buffer
+
+		err := WriteCalendar(&buffer, events, now)
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), "DTSTART;VALUE=DATE:20251231\r\n")
+		assert.NotContains(t, buffer.String(), "DESCRIPTION")
+	})
+
+	t.Run("Text escaped", func(t *testing.T) {
+		events := []*Event{{Uid: "1", Date: now, Summary: `Game; with, special\characters`, Description: "line\nline"}}
+		var buffer bytes.Buffer
+
+		err := WriteCalendar(&buffer, events, now)
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), `SUMMARY:Game\; with\, special\\characters`+"\r\n")
+		assert.Contains(t, buffer.String(), "DESCRIPTION:line\\nline\r\n")
+	})
+
+	t.Run("Long lines folded", func(t *testing.T) {
+		events := []*Event{{Uid: "1", Date: now, Summary: strings.Repeat("ą", 100)}}
+		var buffer bytes.Buffer
+
+		err := WriteCalendar(&buffer, events, now)
+
+		assert.NoError(t, err)
+		var unfolded strings.Builder
+		for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), maxLineLength)
+			if strings.HasPrefix(line, " ") {
+				unfolded.WriteString(line[1:])
+			} else {
+				unfolded.WriteString("\n" + line)
+			}
+		}
+		assert.Contains(t, unfolded.String(), "\nSUMMARY:"+strings.Repeat("ą", 100)+"\n")
+	})
+}
@@ -0,0 +1,55 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	secretPrefix = "ldvcal_"
+	secretLength = 32
+)
+
+// Feed is the calendar feed of a user.
+// The secret token of the feed is only stored as a hash.
+type Feed struct {
+	UserId     uuid.UUID
+	CreatedAt  time.Time
+	LastUsedAt sql.NullTime
+}
+
+// Event is a single all-day event of the calendar.
+type Event struct {
+	// Uid identifies the event across updates of the feed.
+	Uid         string
+	Date        time.Time
+	Summary     string
+	Description string
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func scanFeed(row gotabase.Row) (*Feed, error) {
+	var feed Feed
+	if err := row.Scan(&feed.UserId, &feed.CreatedAt, &feed.LastUsedAt); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
@@ -0,0 +1,161 @@
+package calendar
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// GetFeed returns the calendar feed of the user.
+func GetFeed(userId uuid.UUID) (*Feed, error) {
+	query := `select user_id, created_at, last_used_at from calendar_feeds where user_id = $1`
+	return operations.QueryRow(getDatabase(), scanFeed, query, userId)
+}
+
+// RegenerateFeed creates the calendar feed of the user, replacing the secret of the existing one, if any.
+// The returned secret is only stored as a hash, so it cannot be retrieved again later.
+func RegenerateFeed(userId uuid.UUID) (*Feed, string, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		log.Warnf("Failed to generate calendar secret: %v", err)
+		return nil, "", err
+	}
+
+	query := `insert into calendar_feeds (user_id, token_hash) values ($1, $2)
+		on conflict (user_id) do update set token_hash = excluded.token_hash, created_at = now(), last_used_at = null
+		returning user_id, created_at, last_used_at`
+	feed, err := operations.QueryRow(getDatabase(), scanFeed, query, userId, hashSecret(secret))
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, secret, nil
+}
+
+// DeleteFeed removes the calendar feed of the user, so that its secret can no longer be used.
+func DeleteFeed(userId uuid.UUID) error {
+	query := `delete from calendar_feeds where user_id = $1`
+	return operations.DeleteRow(getDatabase(), query, userId)
+}
+
+// GetFeedBySecret returns the calendar [Feed] matching the secret, updating its last used time.
+func GetFeedBySecret(secret string) (*Feed, error) {
+	query := `update calendar_feeds set last_used_at = now() where token_hash = $1 returning user_id, created_at, last_used_at`
+	return operations.QueryRow(getDatabase(), scanFeed, query, hashSecret(secret))
+}
+
+// GetEvents returns the events of the calendar of the user:
+// releases of games, which are not released yet but have a release date, and optionally starts and ends of playthroughs.
+func GetEvents(userId uuid.UUID, includePlaythroughs bool) ([]*Event, error) {
+	connector := getDatabase()
+	events, err := getReleaseEvents(connector, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !includePlaythroughs {
+		return events, nil
+	}
+
+	playthroughEvents, err := getPlaythroughEvents(connector, userId)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, playthroughEvents...), nil
+}
+
+func getReleaseEvents(connector gotabase.Connector, userId uuid.UUID) ([]*Event, error) {
+	query := `select g.id, g.title, p.name, g.release_date, g.release_date_precision
+		from games g
+		join platforms p on p.id = g.platform_id
+		where g.user_id = $1 and g.deleted_at is null and not g.released and g.release_date is not null
+		order by g.release_date, g.title`
+	return operations.QueryRows(connector, func(row gotabase.Row) (*Event, error) {
+		var id uuid.UUID
+		var title, platform string
+		var releaseDate sql.NullTime
+		var precision games.ReleaseDatePrecision
+		if err := row.Scan(&id, &title, &platform, &releaseDate, &precision); err != nil {
+			return nil, err
+		}
+
+		event := &Event{
+			Uid:         fmt.Sprintf("%s-release@ludivault", id),
+			Date:        releaseDate.Time,
+			Summary:     fmt.Sprintf("%s release", title),
+			Description: fmt.Sprintf("Platform: %s", platform),
+		}
+		if precision != games.ReleaseDateDay {
+			// less precise dates are shown on the first day of their period
+			event.Summary = fmt.Sprintf("%s release (%s)", title, games.FormatReleaseDate(releaseDate, precision))
+		}
+		return event, nil
+	}, query, userId)
+}
+
+func getPlaythroughEvents(connector gotabase.Connector, userId uuid.UUID) ([]*Event, error) {
+	query := `select pt.id, g.title, p.name, pt.start_date, pt.end_date, pt.status
+		from playthroughs pt
+		join games g on g.id = pt.game_id
+		join platforms p on p.id = g.platform_id
+		where g.user_id = $1 and pt.deleted_at is null and g.deleted_at is null
+		order by pt.start_date, g.title`
+	rows, err := connector.QueryRows(query, userId)
+	if err != nil {
+		return nil, operations.Errors.HandleError(err)
+	}
+	defer rows.Close()
+
+	events := make([]*Event, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		var title, platform string
+		var start time.Time
+		var end sql.NullTime
+		var status playthroughs.PlaythroughStatus
+		if err = rows.Scan(&id, &title, &platform, &start, &end, &status); err != nil {
+			return nil, operations.Errors.HandleError(err)
+		}
+
+		description := fmt.Sprintf("Platform: %s\nStatus: %s", platform, status)
+		events = append(events, &Event{
+			Uid:         fmt.Sprintf("%s-start@ludivault", id),
+			Date:        start,
+			Summary:     fmt.Sprintf("Started %s", title),
+			Description: description,
+		})
+		if end.Valid {
+			events = append(events, &Event{
+				Uid:         fmt.Sprintf("%s-end@ludivault", id),
+				Date:        end.Time,
+				Summary:     fmt.Sprintf("%s %s", endVerb(status), title),
+				Description: description,
+			})
+		}
+	}
+	if err = utils.RowsErr(rows); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// endVerb returns the verb used in the summary of the event ending a playthrough, worded after the status of the playthrough.
+func endVerb(status playthroughs.PlaythroughStatus) string {
+	switch status {
+	case playthroughs.PlaythroughCompleted:
+		return "Finished"
+	case playthroughs.PlaythroughDropped:
+		return "Dropped"
+	case playthroughs.PlaythroughRetired:
+		return "Retired"
+	case playthroughs.PlaythroughSuspended:
+		return "Suspended"
+	default:
+		return "Stopped playing"
+	}
+}
@@ -0,0 +1,142 @@
+package calendar
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// platformCount keeps names and short names of the test platforms unique.
+var platformCount int
+
+func makeGame(title string, releaseDate string, precision int, released bool, userId uuid.UUID) uuid.UUID {
+	platformCount++
+	platformId := tests.GetRandomUuid()
+	_, err := getDatabase().Exec(`insert into platforms (id, name, short_name, user_id) values ($1, $2, $2, $3)`, platformId, fmt.Sprintf("p%d", platformCount), userId)
+	tests.PanicOnErr(err)
+	id := tests.GetRandomUuid()
+	query := `insert into games (id, title, platform_id, owned, release_date, release_date_precision, released, user_id) values ($1, $2, $3, false, nullif($4, '')::timestamptz, $5, $6, $7)`
+	_, err = getDatabase().Exec(query, id, title, platformId, releaseDate, precision, released, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makePlaythrough(gameId uuid.UUID, start string, end string, status playthroughs.PlaythroughStatus) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into playthroughs (id, game_id, start_date, end_date, status) values ($1, $2, $3::timestamptz, nullif($4, '')::timestamptz, $5)`
+	_, err := getDatabase().Exec(query, id, gameId, start, end, status)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func TestRegenerateFeed(t *testing.T) {
+	t.Run("Feed created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		feed, secret, err := RegenerateFeed(userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, feed.UserId)
+		assert.True(t, strings.HasPrefix(secret, secretPrefix))
+		found, err := GetFeedBySecret(secret)
+		assert.NoError(t, err)
+		assert.Equal(t, userId, found.UserId)
+		assert.True(t, found.LastUsedAt.Valid)
+	})
+
+	t.Run("Old secret no longer valid", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, oldSecret, err := RegenerateFeed(userId)
+		tests.PanicOnErr(err)
+
+		_, newSecret, err := RegenerateFeed(userId)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, oldSecret, newSecret)
+		_, err = GetFeedBySecret(oldSecret)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		_, err = GetFeedBySecret(newSecret)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteFeed(t *testing.T) {
+	t.Run("Feed deleted", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, secret, err := RegenerateFeed(userId)
+		tests.PanicOnErr(err)
+
+		err = DeleteFeed(userId)
+
+		assert.NoError(t, err)
+		_, err = GetFeedBySecret(secret)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		_, err = GetFeed(userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Feed does not exist", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		err := DeleteFeed(userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestGetEvents(t *testing.T) {
+	tests.GetDatabaseWithCleanup(t)
+	userId := tests.MakeTestUserId(getDatabase())
+	upcoming := makeGame("Upcoming", "2099-08-20T00:00:00Z", 0, false, userId)
+	makeGame("Quarter", "2099-07-01T00:00:00Z", 2, false, userId)
+	makeGame("Released", "2020-01-01T00:00:00Z", 0, true, userId)
+	makeGame("TBA", "", 4, false, userId)
+	played := makeGame("Played", "2020-01-01T00:00:00Z", 0, true, userId)
+	playthroughId := makePlaythrough(played, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z", playthroughs.PlaythroughCompleted)
+	makeGame("Other user", "2099-01-01T00:00:00Z", 0, false, tests.MakeTestUserId(getDatabase()))
+
+	t.Run("Only releases", func(t *testing.T) {
+		events, err := GetEvents(userId, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, "Quarter release (2099-Q3)", events[0].Summary)
+		assert.Equal(t, upcoming.String()+"-release@ludivault", events[1].Uid)
+		assert.Equal(t, "Upcoming release", events[1].Summary)
+		assert.True(t, time.Date(2099, 8, 20, 0, 0, 0, 0, time.UTC).Equal(events[1].Date))
+	})
+
+	t.Run("With playthroughs", func(t *testing.T) {
+		events, err := GetEvents(userId, true)
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 4)
+		assert.Equal(t, playthroughId.String()+"-start@ludivault", events[2].Uid)
+		assert.Equal(t, "Started Played", events[2].Summary)
+		assert.Equal(t, "Finished Played", events[3].Summary)
+		assert.Contains(t, events[3].Description, "Status: Completed")
+	})
+}
+
+func TestGetEventsPlaythroughEnd(t *testing.T) {
+	tests.GetDatabaseWithCleanup(t)
+	userId := tests.MakeTestUserId(getDatabase())
+	gameId := makeGame("Abandoned", "2020-01-01T00:00:00Z", 0, true, userId)
+	makePlaythrough(gameId, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z", playthroughs.PlaythroughDropped)
+
+	events, err := GetEvents(userId, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "Dropped Abandoned", events[1].Summary)
+}
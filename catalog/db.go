@@ -0,0 +1,7 @@
+package catalog
+
+import "github.com/KowalskiPiotr98/gotabase"
+
+var (
+	getDatabase = func() gotabase.Connector { return gotabase.GetConnection() }
+)
@@ -0,0 +1,24 @@
+package catalog
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+)
+
+// Platform is a well-known platform shared by all users of the instance.
+type Platform struct {
+	Id           uuid.UUID
+	Name         string
+	ShortName    string
+	Manufacturer sql.NullString
+	ReleaseYear  sql.NullInt32
+}
+
+func scanPlatform(row gotabase.Row) (*Platform, error) {
+	var platform Platform
+	if err := row.Scan(&platform.Id, &platform.Name, &platform.ShortName, &platform.Manufacturer, &platform.ReleaseYear); err != nil {
+		return nil, err
+	}
+	return &platform, nil
+}
@@ -0,0 +1,18 @@
+package catalog
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/google/uuid"
+)
+
+// GetPlatforms returns all platforms from the shared catalog.
+func GetPlatforms() ([]*Platform, error) {
+	query := `select id, name, short_name, manufacturer, release_year from catalog_platforms order by name`
+	return operations.QueryRows(getDatabase(), scanPlatform, query)
+}
+
+// GetPlatform returns a single [Platform] from the shared catalog.
+func GetPlatform(id uuid.UUID) (*Platform, error) {
+	query := `select id, name, short_name, manufacturer, release_year from catalog_platforms where id = $1`
+	return operations.QueryRow(getDatabase(), scanPlatform, query, id)
+}
@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetPlatforms(t *testing.T) {
+	t.Run("Seeded platforms returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+
+		list, err := GetPlatforms()
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, list)
+		names := make([]string, len(list))
+		for i, platform := range list {
+			names[i] = platform.Name
+		}
+		assert.Contains(t, names, "PC")
+		assert.Contains(t, names, "Nintendo Switch")
+	})
+}
+
+func TestGetPlatform(t *testing.T) {
+	t.Run("Platform exists - returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		list, err := GetPlatforms()
+		tests.PanicOnErr(err)
+
+		platform, err := GetPlatform(list[0].Id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, list[0], platform)
+	})
+
+	t.Run("Platform does not exist - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+
+		_, err := GetPlatform(tests.GetRandomUuid())
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
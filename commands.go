@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/steam"
+	"github.com/google/uuid"
+	"os"
+)
+
+// runCommand runs a maintenance command given as command line arguments, instead of starting the server.
+func runCommand(args []string) error {
+	switch args[0] {
+	case "import-steam":
+		return importSteam(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, available commands: import-steam", args[0])
+	}
+}
+
+func importSteam(args []string) error {
+	flags := flag.NewFlagSet("import-steam", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ludivault import-steam -user <user id> -platform <platform id> [-dry-run] <steamapps directory>")
+		flags.PrintDefaults()
+	}
+	userFlag := flags.String("user", "", "id of the user to import the games for")
+	platformFlag := flags.String("platform", "", "id of the platform of the user to create the games on")
+	dryRun := flags.Bool("dry-run", false, "only show what would be imported")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("steamapps directory is required")
+	}
+
+	userId, err := uuid.Parse(*userFlag)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	platformId, err := uuid.Parse(*platformFlag)
+	if err != nil {
+		return fmt.Errorf("invalid platform id: %w", err)
+	}
+
+	library, err := steam.ScanLibrary(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, folder := range library.MissingFolders {
+		fmt.Fprintf(os.Stderr, "Library folder %s could not be read, skipping\n", folder)
+	}
+
+	result, err := steam.Import(library.Apps, platformId, *dryRun, userId)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		fmt.Println("Dry run, no changes were saved")
+	}
+	for _, app := range result.Created {
+		fmt.Printf("Created: %s (%d)\n", app.Name, app.AppId)
+	}
+	for _, app := range result.Linked {
+		fmt.Printf("Linked existing game: %s (%d)\n", app.Name, app.AppId)
+	}
+	for _, app := range result.Skipped {
+		fmt.Printf("Skipped, already exists: %s (%d)\n", app.Name, app.AppId)
+	}
+	fmt.Printf("%d created, %d linked, %d skipped\n", len(result.Created), len(result.Linked), len(result.Skipped))
+	return nil
+}
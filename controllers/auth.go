@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"github.com/KowalskiPiotr98/ludivault/archive"
 	"github.com/KowalskiPiotr98/ludivault/auth"
 	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/sessions"
 	"github.com/KowalskiPiotr98/ludivault/users"
 	"github.com/gin-gonic/gin"
 	"github.com/markbates/goth/gothic"
@@ -33,6 +35,21 @@ func authCallback(c *gin.Context) {
 		return
 	}
 
+	linking, err := auth.ConsumeIdentityLinking(c)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if linking && auth.IsLoggedIn(c) {
+		if err = linkUserIdentity(&user, c); err != nil {
+			handleError(c, err)
+			return
+		}
+
+		c.Redirect(http.StatusFound, "/")
+		return
+	}
+
 	if err = initUserSession(&user, c); err != nil {
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
@@ -41,6 +58,49 @@ func authCallback(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/")
 }
 
+func linkIdentity(c *gin.Context) {
+	user, err := gothic.CompleteUserAuth(c.Writer, c.Request)
+	if err != nil {
+		if err = auth.MarkIdentityLinking(c); err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		gothic.BeginAuthHandler(c.Writer, c.Request)
+		return
+	}
+
+	if err = linkUserIdentity(&user, c); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/")
+}
+
+func getIdentities(c *gin.Context) {
+	list, err := users.GetIdentities(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapIdentityToDto))
+}
+
+func unlinkIdentity(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	if err = users.UnlinkIdentity(id, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 func logout(c *gin.Context) {
 	if err := auth.RemoveUserSession(c); err != nil {
 		c.AbortWithStatus(http.StatusInternalServerError)
@@ -68,3 +128,64 @@ func getUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.MapUserToDto(user))
 }
+
+func exportUser(c *gin.Context) {
+	result, err := archive.Export(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="ludivault-export.json"`)
+	c.JSON(http.StatusOK, dto.MapArchiveToDto(result))
+}
+
+func deleteUser(c *gin.Context) {
+	if err := users.DeleteUser(auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := auth.RemoveUserSession(c); err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func getSessions(c *gin.Context) {
+	list, err := sessions.GetActiveSessions(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	currentId := auth.GetSessionId(c)
+	c.JSON(http.StatusOK, dto.MapMany(list, func(session *sessions.Session) *dto.SessionDto {
+		return dto.MapSessionToDto(session, currentId)
+	}))
+}
+
+func revokeSession(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	if err = sessions.RevokeSession(id, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func revokeOtherSessions(c *gin.Context) {
+	if err := sessions.RevokeOtherSessions(auth.GetSessionId(c), auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"bytes"
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/calendar"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const calendarExtension = ".ics"
+
+func getCalendarFeed(c *gin.Context) {
+	feed, err := calendar.GetFeed(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapCalendarFeedToDto(feed))
+}
+
+// regenerateCalendarFeed creates the calendar feed of the user, or replaces its secret, so that the old address stops working.
+func regenerateCalendarFeed(c *gin.Context) {
+	feed, secret, err := calendar.RegenerateFeed(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	feedPath := path.Join(c.Request.URL.Path, secret+calendarExtension)
+	c.JSON(http.StatusCreated, dto.MapCreatedCalendarFeedToDto(feed, secret, feedPath))
+}
+
+func deleteCalendarFeed(c *gin.Context) {
+	if err := calendar.DeleteFeed(auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// getCalendar serves the calendar feed identified by the secret in the path, without requiring the user to be logged in.
+// Events of playthroughs are included with ?playthroughs=true.
+func getCalendar(c *gin.Context) {
+	secret, found := strings.CutSuffix(c.Param("token"), calendarExtension)
+	if !found {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	var query struct {
+		Playthroughs bool `form:"playthroughs"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+
+	feed, err := calendar.GetFeedBySecret(secret)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	events, err := calendar.GetEvents(feed.UserId, query.Playthroughs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err = calendar.WriteCalendar(&buffer, events, time.Now()); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buffer.Bytes())
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/catalog"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func getCatalogPlatforms(c *gin.Context) {
+	list, err := catalog.GetPlatforms()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapCatalogPlatformToDto))
+}
+
+func adoptCatalogPlatform(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	platform, err := platforms.AdoptPlatform(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapPlatformToDto(platform))
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/archive"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ArchiveDto struct {
+	Version      int               `json:"version" binding:"required"`
+	ExportedAt   time.Time         `json:"exportedAt"`
+	User         *UserDto          `json:"user"`
+	Platforms    []*PlatformDto    `json:"platforms"`
+	Games        []*GameDto        `json:"games"`
+	Playthroughs []*PlaythroughDto `json:"playthroughs"`
+}
+
+func MapArchiveToDto(archive *archive.Archive) *ArchiveDto {
+	return &ArchiveDto{
+		Version:      archive.Version,
+		ExportedAt:   archive.ExportedAt,
+		User:         MapUserToDto(archive.User),
+		Platforms:    MapMany(archive.Platforms, MapPlatformToDto),
+		Games:        MapMany(archive.Games, MapGameToDto),
+		Playthroughs: MapMany(archive.Playthroughs, MapPlaythroughToDto),
+	}
+}
+
+// MapArchiveDtoToObject maps the archive uploaded for restore.
+// User data is ignored, as the archive is always restored for the current user.
+func MapArchiveDtoToObject(archiveDto *ArchiveDto) *archive.Archive {
+	return &archive.Archive{
+		Version:      archiveDto.Version,
+		ExportedAt:   archiveDto.ExportedAt,
+		Platforms:    MapMany(archiveDto.Platforms, mapArchivedPlatform),
+		Games:        MapMany(archiveDto.Games, mapArchivedGame),
+		Playthroughs: MapMany(archiveDto.Playthroughs, mapArchivedPlaythrough),
+	}
+}
+
+func mapArchivedPlatform(platform *PlatformDto) *platforms.Platform {
+	return &platforms.Platform{
+		Id:        platform.Id,
+		Name:      platform.Name,
+		ShortName: platform.ShortName,
+	}
+}
+
+func mapArchivedGame(game *GameDto) *games.Game {
+	return &games.Game{
+		Id:                   game.Id,
+		PlatformId:           game.PlatformId,
+		Title:                game.Title,
+		Owned:                game.Owned,
+		ReleaseDate:          makeNullTimeFromPointer(game.ReleaseDate),
+		ReleaseDatePrecision: games.ReleaseDatePrecision(game.ReleaseDatePrecision),
+		Released:             game.Released,
+		SteamAppId:           makeNullIntFromPointer(game.SteamAppId),
+	}
+}
+
+func mapArchivedPlaythrough(playthrough *PlaythroughDto) *playthroughs.Playthrough {
+	return &playthroughs.Playthrough{
+		Id:        playthrough.Id,
+		GameId:    playthrough.GameId,
+		StartDate: playthrough.StartDate,
+		EndDate:   makeNullTimeFromPointer(playthrough.EndDate),
+		Status:    playthroughs.PlaythroughStatus(playthrough.Status),
+		Runtime:   makeNullIntFromPointer(playthrough.Runtime),
+	}
+}
+
+type ConflictDto struct {
+	Entity   string    `json:"entity"`
+	SourceId uuid.UUID `json:"sourceId"`
+	Message  string    `json:"message"`
+}
+
+func MapConflictToDto(conflict *archive.Conflict) *ConflictDto {
+	return &ConflictDto{
+		Entity:   conflict.Entity,
+		SourceId: conflict.SourceId,
+		Message:  conflict.Message,
+	}
+}
+
+type RestoreResultDto struct {
+	PlatformsCreated    int            `json:"platformsCreated"`
+	PlatformsMerged     int            `json:"platformsMerged"`
+	GamesCreated        int            `json:"gamesCreated"`
+	PlaythroughsCreated int            `json:"playthroughsCreated"`
+	Conflicts           []*ConflictDto `json:"conflicts"`
+}
+
+func MapRestoreResultToDto(result *archive.RestoreResult) *RestoreResultDto {
+	return &RestoreResultDto{
+		PlatformsCreated:    result.PlatformsCreated,
+		PlatformsMerged:     result.PlatformsMerged,
+		GamesCreated:        result.GamesCreated,
+		PlaythroughsCreated: result.PlaythroughsCreated,
+		Conflicts:           MapMany(result.Conflicts, MapConflictToDto),
+	}
+}
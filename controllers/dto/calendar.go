@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/calendar"
+	"time"
+)
+
+type CalendarFeedDto struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func MapCalendarFeedToDto(feed *calendar.Feed) *CalendarFeedDto {
+	return &CalendarFeedDto{
+		CreatedAt:  feed.CreatedAt,
+		LastUsedAt: makePointerFromNullTime(feed.LastUsedAt),
+	}
+}
+
+// CreatedCalendarFeedDto contains the feed secret, which is only ever returned once, right after the feed is created.
+type CreatedCalendarFeedDto struct {
+	CalendarFeedDto
+	Token string `json:"token"`
+	// Path is the absolute path of the feed, including the secret, to be appended to the base address of the instance.
+	Path string `json:"path"`
+}
+
+func MapCreatedCalendarFeedToDto(feed *calendar.Feed, secret string, path string) *CreatedCalendarFeedDto {
+	return &CreatedCalendarFeedDto{
+		CalendarFeedDto: *MapCalendarFeedToDto(feed),
+		Token:           secret,
+		Path:            path,
+	}
+}
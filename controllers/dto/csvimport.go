@@ -0,0 +1,53 @@
+package dto
+
+import "github.com/KowalskiPiotr98/ludivault/csvimport"
+
+type CsvImportResultDto struct {
+	DryRun              bool                `json:"dryRun"`
+	PlatformsCreated    []string            `json:"platformsCreated"`
+	GamesCreated        int                 `json:"gamesCreated"`
+	PlaythroughsCreated int                 `json:"playthroughsCreated"`
+	Rows                []*CsvRowPreviewDto `json:"rows"`
+	Errors              []*CsvRowErrorDto   `json:"errors"`
+}
+
+type CsvRowPreviewDto struct {
+	Line           int    `json:"line"`
+	Title          string `json:"title"`
+	Platform       string `json:"platform"`
+	NewPlatform    bool   `json:"newPlatform"`
+	HasPlaythrough bool   `json:"hasPlaythrough"`
+}
+
+type CsvRowErrorDto struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func MapCsvImportResultToDto(result *csvimport.Result) *CsvImportResultDto {
+	return &CsvImportResultDto{
+		DryRun:              result.DryRun,
+		PlatformsCreated:    result.PlatformsCreated,
+		GamesCreated:        result.GamesCreated,
+		PlaythroughsCreated: result.PlaythroughsCreated,
+		Rows:                MapMany(result.Rows, mapCsvRowPreviewToDto),
+		Errors:              MapMany(result.Errors, mapCsvRowErrorToDto),
+	}
+}
+
+func mapCsvRowPreviewToDto(row *csvimport.RowPreview) *CsvRowPreviewDto {
+	return &CsvRowPreviewDto{
+		Line:           row.Line,
+		Title:          row.Title,
+		Platform:       row.Platform,
+		NewPlatform:    row.NewPlatform,
+		HasPlaythrough: row.HasPlaythrough,
+	}
+}
+
+func mapCsvRowErrorToDto(rowError *csvimport.RowError) *CsvRowErrorDto {
+	return &CsvRowErrorDto{
+		Line:    rowError.Line,
+		Message: rowError.Message,
+	}
+}
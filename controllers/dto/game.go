@@ -12,35 +12,47 @@ type GameDto struct {
 	Title       string     `json:"title"`
 	Owned       bool       `json:"owned"`
 	ReleaseDate *time.Time `json:"releaseDate,omitempty"`
-	Released    bool       `json:"released"`
+	// ReleaseDatePrecision: 0 - day, 1 - month, 2 - quarter, 3 - year, 4 - to be announced
+	ReleaseDatePrecision int       `json:"releaseDatePrecision"`
+	Released             bool      `json:"released"`
+	SteamAppId           *int      `json:"steamAppId,omitempty"`
+	Tags                 []*TagDto `json:"tags"`
+	// Version matches the ETag of the game, and changes with every change of the game.
+	Version int `json:"version"`
 }
 
 func MapGameToDto(game *games.Game) *GameDto {
 	return &GameDto{
-		Id:          game.Id,
-		PlatformId:  game.PlatformId,
-		Title:       game.Title,
-		Owned:       game.Owned,
-		ReleaseDate: makePointerFromNullTime(game.ReleaseDate),
-		Released:    game.Released,
+		Id:                   game.Id,
+		PlatformId:           game.PlatformId,
+		Title:                game.Title,
+		Owned:                game.Owned,
+		ReleaseDate:          makePointerFromNullTime(game.ReleaseDate),
+		ReleaseDatePrecision: int(game.ReleaseDatePrecision),
+		Released:             game.Released,
+		SteamAppId:           makePointerFromNullInt(game.SteamAppId),
+		Tags:                 MapMany(game.Tags, MapTagToDto),
+		Version:              game.Version,
 	}
 }
 
 type GameEditDto struct {
-	PlatformId  uuid.UUID  `json:"platformId" binding:"required"`
-	Title       string     `json:"title" binding:"required,max=500"`
-	Owned       bool       `json:"owned"`
-	ReleaseDate *time.Time `json:"releaseDate"`
-	Released    bool       `json:"released"`
+	PlatformId           uuid.UUID  `json:"platformId" binding:"required"`
+	Title                string     `json:"title" binding:"required,max=500"`
+	Owned                bool       `json:"owned"`
+	ReleaseDate          *time.Time `json:"releaseDate"`
+	ReleaseDatePrecision int        `json:"releaseDatePrecision" binding:"min=0,max=4"`
+	Released             bool       `json:"released"`
 }
 
 func MapGameEditDtoToObject(id uuid.UUID, game *GameEditDto) *games.Game {
 	return &games.Game{
-		Id:          id,
-		PlatformId:  game.PlatformId,
-		Title:       game.Title,
-		Owned:       game.Owned,
-		ReleaseDate: makeNullTimeFromPointer(game.ReleaseDate),
-		Released:    game.Released,
+		Id:                   id,
+		PlatformId:           game.PlatformId,
+		Title:                game.Title,
+		Owned:                game.Owned,
+		ReleaseDate:          makeNullTimeFromPointer(game.ReleaseDate),
+		ReleaseDatePrecision: games.ReleaseDatePrecision(game.ReleaseDatePrecision),
+		Released:             game.Released,
 	}
 }
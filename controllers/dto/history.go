@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/KowalskiPiotr98/ludivault/audit"
+	"github.com/google/uuid"
+	"time"
+)
+
+type HistoryEntryDto struct {
+	Id         int64            `json:"id"`
+	EntityType audit.EntityType `json:"entityType"`
+	EntityId   uuid.UUID        `json:"entityId"`
+	Action     audit.Action     `json:"action"`
+	Before     json.RawMessage  `json:"before"`
+	After      json.RawMessage  `json:"after"`
+	ChangedAt  time.Time        `json:"changedAt"`
+}
+
+func MapHistoryEntryToDto(entry *audit.Entry) *HistoryEntryDto {
+	return &HistoryEntryDto{
+		Id:         entry.Id,
+		EntityType: entry.EntityType,
+		EntityId:   entry.EntityId,
+		Action:     entry.Action,
+		Before:     makeRawJson(entry.Before),
+		After:      makeRawJson(entry.After),
+		ChangedAt:  entry.ChangedAt,
+	}
+}
+
+// makeRawJson returns the JSON as is, or null if there is none.
+func makeRawJson(value []byte) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -1,21 +1,31 @@
 package dto
 
 import (
+	"github.com/KowalskiPiotr98/ludivault/catalog"
 	"github.com/KowalskiPiotr98/ludivault/platforms"
 	"github.com/google/uuid"
 )
 
 type PlatformDto struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	ShortName string    `json:"shortName"`
+	Id                uuid.UUID  `json:"id"`
+	Name              string     `json:"name"`
+	ShortName         string     `json:"shortName"`
+	CatalogPlatformId *uuid.UUID `json:"catalogPlatformId,omitempty"`
+	Manufacturer      *string    `json:"manufacturer,omitempty"`
+	ReleaseYear       *int       `json:"releaseYear,omitempty"`
+	// Version matches the ETag of the platform, and changes with every change of the platform.
+	Version int `json:"version"`
 }
 
 func MapPlatformToDto(platform *platforms.Platform) *PlatformDto {
 	return &PlatformDto{
-		Id:        platform.Id,
-		Name:      platform.Name,
-		ShortName: platform.ShortName,
+		Id:                platform.Id,
+		Name:              platform.Name,
+		ShortName:         platform.ShortName,
+		CatalogPlatformId: makePointerFromNullUuid(platform.CatalogPlatformId),
+		Manufacturer:      makePointerFromNullString(platform.Manufacturer),
+		ReleaseYear:       makePointerFromNullInt(platform.ReleaseYear),
+		Version:           platform.Version,
 	}
 }
 
@@ -31,3 +41,37 @@ func MapPlatformEditDtoToObject(id uuid.UUID, platform *PlatformEditDto) *platfo
 		ShortName: platform.ShortName,
 	}
 }
+
+type PlatformInUseDto struct {
+	ErrorDto
+	GameCount int `json:"gameCount"`
+}
+
+func MapPlatformInUseErrorToDto(err *platforms.InUseError) *PlatformInUseDto {
+	return &PlatformInUseDto{
+		ErrorDto:  ErrorDto{Error: err.Error()},
+		GameCount: err.GameCount,
+	}
+}
+
+type PlatformMergeResultDto struct {
+	GamesMoved int `json:"gamesMoved"`
+}
+
+type CatalogPlatformDto struct {
+	Id           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	ShortName    string    `json:"shortName"`
+	Manufacturer *string   `json:"manufacturer,omitempty"`
+	ReleaseYear  *int      `json:"releaseYear,omitempty"`
+}
+
+func MapCatalogPlatformToDto(platform *catalog.Platform) *CatalogPlatformDto {
+	return &CatalogPlatformDto{
+		Id:           platform.Id,
+		Name:         platform.Name,
+		ShortName:    platform.ShortName,
+		Manufacturer: makePointerFromNullString(platform.Manufacturer),
+		ReleaseYear:  makePointerFromNullInt(platform.ReleaseYear),
+	}
+}
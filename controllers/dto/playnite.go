@@ -0,0 +1,74 @@
+package dto
+
+import "github.com/KowalskiPiotr98/ludivault/playnite"
+
+type PlayniteImportResultDto struct {
+	DryRun              bool                    `json:"dryRun"`
+	PlatformsCreated    []string                `json:"platformsCreated"`
+	GamesCreated        int                     `json:"gamesCreated"`
+	GamesSkipped        int                     `json:"gamesSkipped"`
+	PlaythroughsCreated int                     `json:"playthroughsCreated"`
+	Games               []*PlayniteGameDto      `json:"games"`
+	Errors              []*PlayniteGameErrorDto `json:"errors"`
+	UnmappedFields      map[string]int          `json:"unmappedFields"`
+	UnmappedStatuses    map[string]int          `json:"unmappedStatuses"`
+}
+
+type PlayniteGameDto struct {
+	Index       int                     `json:"index"`
+	Title       string                  `json:"title"`
+	Platform    string                  `json:"platform"`
+	NewPlatform bool                    `json:"newPlatform"`
+	Skipped     bool                    `json:"skipped"`
+	Playthrough *PlaynitePlaythroughDto `json:"playthrough,omitempty"`
+}
+
+type PlaynitePlaythroughDto struct {
+	Status  int `json:"status"`
+	Runtime int `json:"runtime"`
+}
+
+type PlayniteGameErrorDto struct {
+	Index   int    `json:"index"`
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+}
+
+func MapPlayniteImportResultToDto(result *playnite.Result) *PlayniteImportResultDto {
+	return &PlayniteImportResultDto{
+		DryRun:              result.DryRun,
+		PlatformsCreated:    result.PlatformsCreated,
+		GamesCreated:        result.GamesCreated,
+		GamesSkipped:        result.GamesSkipped,
+		PlaythroughsCreated: result.PlaythroughsCreated,
+		Games:               MapMany(result.Games, mapPlayniteGameToDto),
+		Errors:              MapMany(result.Errors, mapPlayniteGameErrorToDto),
+		UnmappedFields:      result.UnmappedFields,
+		UnmappedStatuses:    result.UnmappedStatuses,
+	}
+}
+
+func mapPlayniteGameToDto(game *playnite.GamePreview) *PlayniteGameDto {
+	mapped := &PlayniteGameDto{
+		Index:       game.Index,
+		Title:       game.Title,
+		Platform:    game.Platform,
+		NewPlatform: game.NewPlatform,
+		Skipped:     game.Skipped,
+	}
+	if game.Playthrough != nil {
+		mapped.Playthrough = &PlaynitePlaythroughDto{
+			Status:  int(game.Playthrough.Status),
+			Runtime: game.Playthrough.Runtime,
+		}
+	}
+	return mapped
+}
+
+func mapPlayniteGameErrorToDto(gameError *playnite.GameError) *PlayniteGameErrorDto {
+	return &PlayniteGameErrorDto{
+		Index:   gameError.Index,
+		Title:   gameError.Title,
+		Message: gameError.Message,
+	}
+}
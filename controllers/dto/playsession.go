@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/playsessions"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PlaySessionDto struct {
+	Id            uuid.UUID  `json:"id"`
+	PlaythroughId uuid.UUID  `json:"playthroughId"`
+	StartTime     time.Time  `json:"startTime"`
+	EndTime       *time.Time `json:"endTime,omitempty"`
+	Duration      *int       `json:"duration,omitempty"`
+	Note          *string    `json:"note,omitempty"`
+}
+
+func MapPlaySessionToDto(session *playsessions.PlaySession) *PlaySessionDto {
+	return &PlaySessionDto{
+		Id:            session.Id,
+		PlaythroughId: session.PlaythroughId,
+		StartTime:     session.StartTime,
+		EndTime:       makePointerFromNullTime(session.EndTime),
+		Duration:      makePointerFromNullInt(session.Duration),
+		Note:          makePointerFromNullString(session.Note),
+	}
+}
+
+type PlaySessionEditDto struct {
+	StartTime time.Time  `json:"startTime" binding:"required"`
+	EndTime   *time.Time `json:"endTime" binding:"required_without=Duration,omitempty,gtefield=StartTime"`
+	Duration  *int       `json:"duration" binding:"required_without=EndTime,omitempty,min=0"`
+	Note      *string    `json:"note" binding:"omitempty,max=1000"`
+}
+
+func MapPlaySessionEditDtoToObject(id uuid.UUID, playthroughId uuid.UUID, session *PlaySessionEditDto) *playsessions.PlaySession {
+	return &playsessions.PlaySession{
+		Id:            id,
+		PlaythroughId: playthroughId,
+		StartTime:     session.StartTime,
+		EndTime:       makeNullTimeFromPointer(session.EndTime),
+		Duration:      makeNullIntFromPointer(session.Duration),
+		Note:          makeNullStringFromPointer(session.Note),
+	}
+}
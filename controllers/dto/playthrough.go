@@ -13,6 +13,11 @@ type PlaythroughDto struct {
 	EndDate   *time.Time `json:"endDate,omitempty"`
 	Status    int        `json:"status"`
 	Runtime   *int       `json:"runtime,omitempty"`
+
+	SessionCount int        `json:"sessionCount"`
+	LastPlayed   *time.Time `json:"lastPlayed,omitempty"`
+	// Version matches the ETag of the playthrough, and changes with every change of the playthrough.
+	Version int `json:"version"`
 }
 
 func MapPlaythroughToDto(playthrough *playthroughs.Playthrough) *PlaythroughDto {
@@ -23,6 +28,10 @@ func MapPlaythroughToDto(playthrough *playthroughs.Playthrough) *PlaythroughDto
 		EndDate:   makePointerFromNullTime(playthrough.EndDate),
 		Status:    int(playthrough.Status),
 		Runtime:   makePointerFromNullInt(playthrough.Runtime),
+
+		SessionCount: playthrough.SessionCount,
+		LastPlayed:   makePointerFromNullTime(playthrough.LastPlayed),
+		Version:      playthrough.Version,
 	}
 }
 
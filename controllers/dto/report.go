@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/reports"
+	"github.com/google/uuid"
+	"time"
+)
+
+type YearReportDto struct {
+	Year     int `json:"year"`
+	Started  int `json:"started"`
+	Finished int `json:"finished"`
+	Dropped  int `json:"dropped"`
+
+	LongestPlaythrough *PlaythroughSummaryDto `json:"longestPlaythrough,omitempty"`
+	MostPlayedPlatform *PlatformSummaryDto    `json:"mostPlayedPlatform,omitempty"`
+	FirstCompletion    *PlaythroughSummaryDto `json:"firstCompletion,omitempty"`
+	LastCompletion     *PlaythroughSummaryDto `json:"lastCompletion,omitempty"`
+
+	Months []*MonthSummaryDto `json:"months"`
+}
+
+type PlaythroughSummaryDto struct {
+	PlaythroughId uuid.UUID  `json:"playthroughId"`
+	GameId        uuid.UUID  `json:"gameId"`
+	Title         string     `json:"title"`
+	PlatformName  string     `json:"platformName"`
+	StartDate     time.Time  `json:"startDate"`
+	EndDate       *time.Time `json:"endDate,omitempty"`
+	Runtime       *int       `json:"runtime,omitempty"`
+}
+
+type PlatformSummaryDto struct {
+	PlatformId   uuid.UUID `json:"platformId"`
+	Name         string    `json:"name"`
+	Minutes      int       `json:"minutes"`
+	Playthroughs int       `json:"playthroughs"`
+}
+
+type MonthSummaryDto struct {
+	Month    int `json:"month"`
+	Started  int `json:"started"`
+	Finished int `json:"finished"`
+	Dropped  int `json:"dropped"`
+}
+
+func MapYearReportToDto(report *reports.YearReport) *YearReportDto {
+	result := &YearReportDto{
+		Year:               report.Year,
+		Started:            report.Started,
+		Finished:           report.Finished,
+		Dropped:            report.Dropped,
+		LongestPlaythrough: mapPlaythroughSummaryToDto(report.LongestPlaythrough),
+		FirstCompletion:    mapPlaythroughSummaryToDto(report.FirstCompletion),
+		LastCompletion:     mapPlaythroughSummaryToDto(report.LastCompletion),
+		Months:             MapMany(report.Months, mapMonthSummaryToDto),
+	}
+	if report.MostPlayedPlatform != nil {
+		result.MostPlayedPlatform = &PlatformSummaryDto{
+			PlatformId:   report.MostPlayedPlatform.PlatformId,
+			Name:         report.MostPlayedPlatform.Name,
+			Minutes:      report.MostPlayedPlatform.Minutes,
+			Playthroughs: report.MostPlayedPlatform.Playthroughs,
+		}
+	}
+	return result
+}
+
+func mapPlaythroughSummaryToDto(summary *reports.PlaythroughSummary) *PlaythroughSummaryDto {
+	if summary == nil {
+		return nil
+	}
+	return &PlaythroughSummaryDto{
+		PlaythroughId: summary.PlaythroughId,
+		GameId:        summary.GameId,
+		Title:         summary.Title,
+		PlatformName:  summary.PlatformName,
+		StartDate:     summary.StartDate,
+		EndDate:       makePointerFromNullTime(summary.EndDate),
+		Runtime:       makePointerFromNullInt(summary.Runtime),
+	}
+}
+
+func mapMonthSummaryToDto(summary *reports.MonthSummary) *MonthSummaryDto {
+	return &MonthSummaryDto{
+		Month:    int(summary.Month),
+		Started:  summary.Started,
+		Finished: summary.Finished,
+		Dropped:  summary.Dropped,
+	}
+}
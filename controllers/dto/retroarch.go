@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/KowalskiPiotr98/ludivault/retroarch"
+
+type RetroArchImportResultDto struct {
+	DryRun              bool     `json:"dryRun"`
+	PlatformsCreated    []string `json:"platformsCreated"`
+	GamesCreated        int      `json:"gamesCreated"`
+	GamesSkipped        int      `json:"gamesSkipped"`
+	PlaythroughsCreated int      `json:"playthroughsCreated"`
+	PlaythroughsUpdated int      `json:"playthroughsUpdated"`
+	UnmatchedLogs       []string `json:"unmatchedLogs"`
+}
+
+func MapRetroArchImportResultToDto(result *retroarch.ImportResult) *RetroArchImportResultDto {
+	return &RetroArchImportResultDto{
+		DryRun:              result.DryRun,
+		PlatformsCreated:    result.PlatformsCreated,
+		GamesCreated:        result.GamesCreated,
+		GamesSkipped:        result.GamesSkipped,
+		PlaythroughsCreated: result.PlaythroughsCreated,
+		PlaythroughsUpdated: result.PlaythroughsUpdated,
+		UnmatchedLogs:       result.UnmatchedLogs,
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/sessions"
+	"github.com/google/uuid"
+	"time"
+)
+
+type SessionDto struct {
+	Id         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	IpAddress  string    `json:"ipAddress"`
+	UserAgent  string    `json:"userAgent"`
+	Current    bool      `json:"current"`
+}
+
+func MapSessionToDto(session *sessions.Session, currentId uuid.UUID) *SessionDto {
+	return &SessionDto{
+		Id:         session.Id,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		IpAddress:  session.IpAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.Id == currentId,
+	}
+}
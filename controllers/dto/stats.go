@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/stats"
+	"github.com/google/uuid"
+)
+
+type StatsDto struct {
+	Statuses              []*StatusCountDto     `json:"statuses"`
+	CompletionsPerMonth   []*MonthCountDto      `json:"completionsPerMonth"`
+	RuntimePerPlatform    []*PlatformRuntimeDto `json:"runtimePerPlatform"`
+	AverageDaysToComplete *float64              `json:"averageDaysToComplete,omitempty"`
+	Backlog               *BacklogDto           `json:"backlog"`
+}
+
+type StatusCountDto struct {
+	Status int `json:"status"`
+	Count  int `json:"count"`
+}
+
+type MonthCountDto struct {
+	// Month is formatted as YYYY-MM.
+	Month string `json:"month"`
+	Count int    `json:"count"`
+}
+
+type PlatformRuntimeDto struct {
+	PlatformId uuid.UUID `json:"platformId"`
+	Name       string    `json:"name"`
+	Minutes    int       `json:"minutes"`
+}
+
+type BacklogDto struct {
+	Owned   int `json:"owned"`
+	Unowned int `json:"unowned"`
+}
+
+func MapStatsToDto(stats *stats.Stats) *StatsDto {
+	var average *float64
+	if stats.AverageDaysToComplete.Valid {
+		average = &stats.AverageDaysToComplete.Float64
+	}
+
+	return &StatsDto{
+		Statuses:              MapMany(stats.Statuses, mapStatusCountToDto),
+		CompletionsPerMonth:   MapMany(stats.CompletionsPerMonth, mapMonthCountToDto),
+		RuntimePerPlatform:    MapMany(stats.RuntimePerPlatform, mapPlatformRuntimeToDto),
+		AverageDaysToComplete: average,
+		Backlog: &BacklogDto{
+			Owned:   stats.Backlog.Owned,
+			Unowned: stats.Backlog.Unowned,
+		},
+	}
+}
+
+func mapStatusCountToDto(count *stats.StatusCount) *StatusCountDto {
+	return &StatusCountDto{
+		Status: int(count.Status),
+		Count:  count.Count,
+	}
+}
+
+func mapMonthCountToDto(count *stats.MonthCount) *MonthCountDto {
+	return &MonthCountDto{
+		Month: count.Month.Format("2006-01"),
+		Count: count.Count,
+	}
+}
+
+func mapPlatformRuntimeToDto(runtime *stats.PlatformRuntime) *PlatformRuntimeDto {
+	return &PlatformRuntimeDto{
+		PlatformId: runtime.PlatformId,
+		Name:       runtime.Name,
+		Minutes:    runtime.Minutes,
+	}
+}
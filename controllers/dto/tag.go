@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/tags"
+	"github.com/google/uuid"
+)
+
+type TagDto struct {
+	Id   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func MapTagToDto(tag *tags.Tag) *TagDto {
+	return &TagDto{
+		Id:   tag.Id,
+		Name: tag.Name,
+	}
+}
+
+type TagEditDto struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func MapTagEditDtoToObject(id uuid.UUID, tag *TagEditDto) *tags.Tag {
+	return &tags.Tag{
+		Id:   id,
+		Name: tag.Name,
+	}
+}
@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/timers"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TimerDto struct {
+	PlaythroughId  uuid.UUID `json:"playthroughId"`
+	StartedAt      time.Time `json:"startedAt"`
+	ElapsedSeconds int       `json:"elapsedSeconds"`
+	ElapsedMinutes int       `json:"elapsedMinutes"`
+}
+
+func MapTimerToDto(timer *timers.Timer) *TimerDto {
+	return &TimerDto{
+		PlaythroughId:  timer.PlaythroughId,
+		StartedAt:      timer.StartedAt,
+		ElapsedSeconds: int(timer.Elapsed / time.Second),
+		ElapsedMinutes: timer.ElapsedMinutes(),
+	}
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/tokens"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TokenDto struct {
+	Id         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scope      int        `json:"scope"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+func MapTokenToDto(token *tokens.Token) *TokenDto {
+	return &TokenDto{
+		Id:         token.Id,
+		Name:       token.Name,
+		Scope:      int(token.Scope),
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: makePointerFromNullTime(token.LastUsedAt),
+		ExpiresAt:  makePointerFromNullTime(token.ExpiresAt),
+	}
+}
+
+// CreatedTokenDto contains the token secret, which is only ever returned once, right after the token is created.
+type CreatedTokenDto struct {
+	TokenDto
+	Token string `json:"token"`
+}
+
+func MapCreatedTokenToDto(token *tokens.Token, secret string) *CreatedTokenDto {
+	return &CreatedTokenDto{
+		TokenDto: *MapTokenToDto(token),
+		Token:    secret,
+	}
+}
+
+type TokenEditDto struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scope     int        `json:"scope" binding:"min=0,max=1"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func MapTokenEditDtoToObject(token *TokenEditDto) *tokens.Token {
+	return &tokens.Token{
+		Name:      token.Name,
+		Scope:     tokens.TokenScope(token.Scope),
+		ExpiresAt: makeNullTimeFromPointer(token.ExpiresAt),
+	}
+}
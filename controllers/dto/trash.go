@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/trash"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TrashItemDto struct {
+	Type      trash.ItemType `json:"type"`
+	Id        uuid.UUID      `json:"id"`
+	Name      string         `json:"name"`
+	DeletedAt time.Time      `json:"deletedAt"`
+}
+
+func MapTrashItemToDto(item *trash.Item) *TrashItemDto {
+	return &TrashItemDto{
+		Type:      item.Type,
+		Id:        item.Id,
+		Name:      item.Name,
+		DeletedAt: item.DeletedAt,
+	}
+}
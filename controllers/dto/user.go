@@ -3,20 +3,35 @@ package dto
 import (
 	"github.com/KowalskiPiotr98/ludivault/users"
 	"github.com/google/uuid"
+	"time"
 )
 
 type UserDto struct {
+	Id    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func MapUserToDto(user *users.User) *UserDto {
+	return &UserDto{
+		Id:    user.Id,
+		Email: user.Email,
+	}
+}
+
+type IdentityDto struct {
 	Id           uuid.UUID `json:"id"`
 	ProviderId   string    `json:"providerId"`
 	ProviderName string    `json:"providerName"`
 	Email        string    `json:"email"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
 
-func MapUserToDto(user *users.User) *UserDto {
-	return &UserDto{
-		Id:           user.Id,
-		ProviderId:   user.ProviderId,
-		ProviderName: user.ProviderName,
-		Email:        user.Email,
+func MapIdentityToDto(identity *users.Identity) *IdentityDto {
+	return &IdentityDto{
+		Id:           identity.Id,
+		ProviderId:   identity.ProviderId,
+		ProviderName: identity.ProviderName,
+		Email:        identity.Email,
+		CreatedAt:    identity.CreatedAt,
 	}
 }
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"database/sql"
+	"github.com/google/uuid"
 	"time"
 )
 
@@ -31,6 +32,20 @@ func makePointerFromNullTime(time sql.NullTime) *time.Time {
 	return nil
 }
 
+func makePointerFromNullString(value sql.NullString) *string {
+	if value.Valid {
+		return &value.String
+	}
+	return nil
+}
+
+func makePointerFromNullUuid(value uuid.NullUUID) *uuid.UUID {
+	if value.Valid {
+		return &value.UUID
+	}
+	return nil
+}
+
 func makePointerFromNullInt(value sql.NullInt32) *int {
 	if value.Valid {
 		typedInt := int(value.Int32)
@@ -39,6 +54,26 @@ func makePointerFromNullInt(value sql.NullInt32) *int {
 	return nil
 }
 
+func makeNullIntFromPointer(value *int) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{
+		Valid: true,
+		Int32: int32(*value),
+	}
+}
+
+func makeNullStringFromPointer(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{
+		Valid:  true,
+		String: *value,
+	}
+}
+
 func makeNullInt(value int) sql.NullInt32 {
 	if value == 0 {
 		return sql.NullInt32{}
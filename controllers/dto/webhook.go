@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
+	"github.com/google/uuid"
+	"time"
+)
+
+// WebhookDto describes a webhook, without its secret, which is never returned.
+type WebhookDto struct {
+	Id        uuid.UUID            `json:"id"`
+	Url       string               `json:"url"`
+	Events    []webhooks.EventType `json:"events"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+func MapWebhookToDto(webhook *webhooks.Webhook) *WebhookDto {
+	return &WebhookDto{
+		Id:        webhook.Id,
+		Url:       webhook.Url,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// WebhookEditDto is used to create and update webhooks.
+// The secret is required when creating a webhook, and left unchanged when empty in an update.
+type WebhookEditDto struct {
+	Url    string               `json:"url" binding:"required,http_url,max=2000"`
+	Secret string               `json:"secret" binding:"omitempty,min=16,max=200"`
+	Events []webhooks.EventType `json:"events" binding:"required,min=1,unique,dive,oneof=game.created playthrough.completed playthrough.dropped"`
+}
+
+func MapWebhookEditDtoToObject(id uuid.UUID, webhook *WebhookEditDto) *webhooks.Webhook {
+	return &webhooks.Webhook{
+		Id:     id,
+		Url:    webhook.Url,
+		Secret: webhook.Secret,
+		Events: webhook.Events,
+	}
+}
+
+type WebhookDeliveryDto struct {
+	Id             uuid.UUID               `json:"id"`
+	Event          webhooks.EventType      `json:"event"`
+	Payload        json.RawMessage         `json:"payload"`
+	Status         webhooks.DeliveryStatus `json:"status"`
+	Attempts       int                     `json:"attempts"`
+	NextAttemptAt  *time.Time              `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time              `json:"lastAttemptAt,omitempty"`
+	ResponseStatus *int                    `json:"responseStatus,omitempty"`
+	Error          *string                 `json:"error,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+}
+
+func MapWebhookDeliveryToDto(delivery *webhooks.Delivery) *WebhookDeliveryDto {
+	result := &WebhookDeliveryDto{
+		Id:             delivery.Id,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  makePointerFromNullTime(delivery.LastAttemptAt),
+		ResponseStatus: makePointerFromNullInt(delivery.ResponseStatus),
+		Error:          makePointerFromNullString(delivery.Error),
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == webhooks.DeliveryPending {
+		result.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// formatETag returns the strong entity tag of the item version.
+func formatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseETag reads the item version back from an entity tag.
+// Weak tags are only accepted when weak is set, as If-Match requires a strong comparison.
+func parseETag(tag string, weak bool) (int, bool) {
+	tag = strings.TrimSpace(tag)
+	if weak {
+		tag = strings.TrimPrefix(tag, "W/")
+	}
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// respondWithETag sends the item with its version as the ETag.
+// GET requests with a matching If-None-Match header get an empty 304 response instead.
+func respondWithETag(c *gin.Context, status int, version int, item any) {
+	c.Header("ETag", formatETag(version))
+	if c.Request.Method == http.MethodGet && matchesNoneOf(c.GetHeader("If-None-Match"), version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(status, item)
+}
+
+// matchesNoneOf tells whether the If-None-Match header value matches the version, using the weak comparison.
+func matchesNoneOf(header string, version int) bool {
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if tagVersion, ok := parseETag(tag, true); ok && tagVersion == version {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIfMatch returns the version of the item expected by the If-Match header, which is required to change the item.
+// The wildcard matches any version and is returned as 0.
+//
+// Requests without the header are aborted with 428 and requests with a header, which cannot match any version, with 412.
+func parseIfMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.AbortWithStatusJSON(http.StatusPreconditionRequired, dto.ErrorDto{Error: "If-Match header with the ETag of the item is required"})
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+	version, ok := parseETag(header, false)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, dto.ErrorDto{Error: "If-Match header must hold a single ETag of the item"})
+		return 0, false
+	}
+	return version, true
+}
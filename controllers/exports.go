@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/csvexport"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+)
+
+func exportGamesCsv(c *gin.Context) {
+	streamCsv(c, "games.csv", csvexport.ExportGames)
+}
+
+func exportPlaythroughsCsv(c *gin.Context) {
+	streamCsv(c, "playthroughs.csv", csvexport.ExportPlaythroughs)
+}
+
+// streamCsv writes the export directly to the response, accepting the same filters as the list of games.
+func streamCsv(c *gin.Context, fileName string, export func(w io.Writer, filter games.Filter, userId uuid.UUID) error) {
+	var query gameFilterQuery
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+	filter, err := query.toFilter(c)
+	if err != nil {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+
+	// once streaming has started the status cannot be changed anymore, so errors can only be logged
+	if err = export(c.Writer, filter, auth.GetUserId(c)); err != nil {
+		log.Warnf("Failed to export %s: %v", fileName, err)
+	}
+}
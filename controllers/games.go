@@ -4,21 +4,62 @@ import (
 	"github.com/KowalskiPiotr98/ludivault/auth"
 	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
 	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
 	"github.com/KowalskiPiotr98/ludivault/playthroughs"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"net/http"
+	"time"
 )
 
+// gameFilterQuery holds the query parameters used to filter lists of games.
+type gameFilterQuery struct {
+	Title      string `form:"title"`
+	Released   *bool  `form:"released"`
+	Owned      *bool  `form:"owned"`
+	InProgress *bool  `form:"inProgress"`
+
+	// ReleaseFrom and ReleaseTo are both inclusive.
+	ReleaseFrom *time.Time `form:"releaseFrom" time_format:"2006-01-02"`
+	ReleaseTo   *time.Time `form:"releaseTo" time_format:"2006-01-02"`
+
+	TagsAny  []string `form:"tagsAny"`
+	TagsAll  []string `form:"tagsAll"`
+	TagsNone []string `form:"tagsNone"`
+}
+
+func (q *gameFilterQuery) toFilter(c *gin.Context) (games.Filter, error) {
+	filter := games.Filter{
+		Title:       q.Title,
+		Owned:       q.Owned,
+		Released:    q.Released,
+		InProgress:  q.InProgress,
+		ReleaseFrom: q.ReleaseFrom,
+	}
+	if q.ReleaseTo != nil {
+		end := q.ReleaseTo.AddDate(0, 0, 1)
+		filter.ReleaseTo = &end
+	}
+	var err error
+	if filter.Tags.AnyOf, err = parseUuids(c, q.TagsAny); err != nil {
+		return filter, err
+	}
+	if filter.Tags.AllOf, err = parseUuids(c, q.TagsAll); err != nil {
+		return filter, err
+	}
+	if filter.Tags.NoneOf, err = parseUuids(c, q.TagsNone); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 func getGames(c *gin.Context) {
 	model := struct {
-		Limit      int    `form:"limit" binding:"min=1,max=100"`
-		Offset     int    `form:"offset" binding:"min=0"`
-		Title      string `form:"title"`
-		Released   *bool  `form:"released"`
-		Owned      *bool  `form:"owned"`
-		InProgress *bool  `form:"inProgress"`
+		Limit  int    `form:"limit" binding:"min=1,max=100"`
+		Offset int    `form:"offset" binding:"min=0"`
+		Sort   string `form:"sort" binding:"omitempty,oneof=title releaseDate"`
+		gameFilterQuery
 	}{
 		Limit:  20,
 		Offset: 0,
@@ -26,9 +67,28 @@ func getGames(c *gin.Context) {
 	if err := c.MustBindWith(&model, binding.Query); err != nil {
 		return
 	}
+	filter, err := model.toFilter(c)
+	if err != nil {
+		return
+	}
+
+	sort := games.SortByTitle
+	if model.Sort == "releaseDate" {
+		sort = games.SortByReleaseDate
+	}
+
+	list, err := games.GetGames(model.Offset, model.Limit, auth.GetUserId(c), filter, sort)
+
+	if err != nil {
+		handleError(c, err)
+		return
+	}
 
-	list, err := games.GetGames(model.Offset, model.Limit, auth.GetUserId(c), model.Title, model.Owned, model.Released, model.InProgress)
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapGameToDto))
+}
 
+func getUpcomingGames(c *gin.Context) {
+	list, err := games.GetUpcomingGames(auth.GetUserId(c))
 	if err != nil {
 		handleError(c, err)
 		return
@@ -49,7 +109,7 @@ func getGame(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapGameToDto(item))
+	respondWithETag(c, http.StatusOK, item.Version, dto.MapGameToDto(item))
 }
 
 func getPlaythroughsForGame(c *gin.Context) {
@@ -71,13 +131,20 @@ func createGame(c *gin.Context) {
 		return
 	}
 
+	platformId, err := platforms.ResolvePlatformId(model.PlatformId, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	model.PlatformId = platformId
+
 	mapped := dto.MapGameEditDtoToObject(uuid.Nil, &model)
-	if err := games.CreateGame(mapped, auth.GetUserId(c)); err != nil {
+	if err = games.CreateGame(mapped, auth.GetUserId(c)); err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.MapGameToDto(mapped))
+	respondWithETag(c, http.StatusCreated, mapped.Version, dto.MapGameToDto(mapped))
 }
 
 func updateGame(c *gin.Context) {
@@ -85,18 +152,30 @@ func updateGame(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 	var model dto.GameEditDto
 	if c.MustBindWith(&model, binding.JSON) != nil {
 		return
 	}
 
+	platformId, err := platforms.ResolvePlatformId(model.PlatformId, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	model.PlatformId = platformId
+
 	mapped := dto.MapGameEditDtoToObject(id, &model)
+	mapped.Version = version
 	if err = games.UpdateGame(mapped, auth.GetUserId(c)); err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapGameToDto(mapped))
+	respondWithETag(c, http.StatusOK, mapped.Version, dto.MapGameToDto(mapped))
 }
 
 func deleteGame(c *gin.Context) {
@@ -104,8 +183,12 @@ func deleteGame(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 
-	err = games.DeleteGame(id, auth.GetUserId(c))
+	err = games.DeleteGame(id, version, auth.GetUserId(c))
 	if err != nil {
 		handleError(c, err)
 		return
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/audit"
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+func getHistory(c *gin.Context) {
+	model := struct {
+		Limit    int    `form:"limit" binding:"min=1,max=100"`
+		Offset   int    `form:"offset" binding:"min=0"`
+		Entity   string `form:"entity" binding:"omitempty,oneof=game platform playthrough"`
+		EntityId string `form:"entityId" binding:"omitempty,uuid"`
+		// From and To are both inclusive.
+		From *time.Time `form:"from" time_format:"2006-01-02"`
+		To   *time.Time `form:"to" time_format:"2006-01-02"`
+	}{
+		Limit:  20,
+		Offset: 0,
+	}
+	if err := c.MustBindWith(&model, binding.Query); err != nil {
+		return
+	}
+
+	filter := audit.Filter{
+		EntityType: audit.EntityType(model.Entity),
+		From:       model.From,
+	}
+	if model.EntityId != "" {
+		filter.EntityId = uuid.NullUUID{UUID: uuid.MustParse(model.EntityId), Valid: true}
+	}
+	if model.To != nil {
+		end := model.To.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	list, err := audit.GetEntries(model.Offset, model.Limit, filter, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapHistoryEntryToDto))
+}
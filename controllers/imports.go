@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/KowalskiPiotr98/ludivault/archive"
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/csvimport"
+	"github.com/KowalskiPiotr98/ludivault/playnite"
+	"github.com/KowalskiPiotr98/ludivault/retroarch"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// maxImportFileSize limits the size of files uploaded for import.
+const maxImportFileSize = 10 << 20
+
+func importArchive(c *gin.Context) {
+	var model dto.ArchiveDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	result, err := archive.Restore(dto.MapArchiveDtoToObject(&model), auth.GetUserId(c))
+	if errors.Is(err, archive.UnsupportedVersionErr) || errors.Is(err, archive.InvalidArchiveErr) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.MapRestoreResultToDto(result))
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapRestoreResultToDto(result))
+}
+
+// importCsv accepts the CSV file either as a multipart upload in the "file" field, or as the raw request body.
+// With ?dryRun=true nothing is saved, and the response shows what would be created.
+func importCsv(c *gin.Context) {
+	var query struct {
+		DryRun bool `form:"dryRun"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+
+	file, ok := getImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := csvimport.Import(file, query.DryRun, auth.GetUserId(c))
+	if errors.Is(err, csvimport.InvalidFileErr) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.MapCsvImportResultToDto(result))
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if query.DryRun {
+		c.JSON(http.StatusOK, dto.MapCsvImportResultToDto(result))
+		return
+	}
+	c.JSON(http.StatusCreated, dto.MapCsvImportResultToDto(result))
+}
+
+// importPlaynite accepts the library JSON either as a multipart upload in the "file" field, or as the raw request body.
+// With ?dryRun=true nothing is saved, and the response shows what would be created.
+func importPlaynite(c *gin.Context) {
+	var query struct {
+		DryRun bool `form:"dryRun"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+
+	file, ok := getImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := playnite.Import(file, query.DryRun, auth.GetUserId(c))
+	if errors.Is(err, playnite.InvalidFileErr) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.MapPlayniteImportResultToDto(result))
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if query.DryRun {
+		c.JSON(http.StatusOK, dto.MapPlayniteImportResultToDto(result))
+		return
+	}
+	c.JSON(http.StatusCreated, dto.MapPlayniteImportResultToDto(result))
+}
+
+// importRetroArch accepts playlists (.lpl) and runtime logs (.lrtl) as a multipart upload in the "files" field.
+// With ?dryRun=true nothing is saved, and the response shows what would be changed.
+func importRetroArch(c *gin.Context) {
+	var query struct {
+		DryRun bool `form:"dryRun"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: "files are missing"})
+		return
+	}
+
+	var playlists []*retroarch.Playlist
+	var logs []*retroarch.RuntimeLog
+	for _, header := range form.File["files"] {
+		switch strings.ToLower(path.Ext(header.Filename)) {
+		case ".lpl":
+			playlist, err := parseRetroArchFile(header, retroarch.ParsePlaylist)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: err.Error()})
+				return
+			}
+			playlists = append(playlists, playlist)
+		case ".lrtl":
+			runtimeLog, err := parseRetroArchFile(header, retroarch.ParseRuntimeLog)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: err.Error()})
+				return
+			}
+			logs = append(logs, runtimeLog)
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: "unsupported file: " + header.Filename})
+			return
+		}
+	}
+
+	result, err := retroarch.Import(playlists, logs, query.DryRun, auth.GetUserId(c))
+	if errors.Is(err, retroarch.NoPlaylistsErr) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if query.DryRun {
+		c.JSON(http.StatusOK, dto.MapRetroArchImportResultToDto(result))
+		return
+	}
+	c.JSON(http.StatusCreated, dto.MapRetroArchImportResultToDto(result))
+}
+
+func parseRetroArchFile[T any](header *multipart.FileHeader, parse func(string, io.Reader) (T, error)) (T, error) {
+	file, err := header.Open()
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+	defer file.Close()
+	return parse(header.Filename, file)
+}
+
+// getImportFile returns the uploaded file, aborting the request if it is missing.
+func getImportFile(c *gin.Context) (io.ReadCloser, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return c.Request.Body, true
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: "file is missing"})
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return nil, false
+	}
+	return file, true
+}
@@ -33,7 +33,7 @@ func getPlatform(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapPlatformToDto(item))
+	respondWithETag(c, http.StatusOK, item.Version, dto.MapPlatformToDto(item))
 }
 
 func createPlatform(c *gin.Context) {
@@ -48,7 +48,7 @@ func createPlatform(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.MapPlatformToDto(mapped))
+	respondWithETag(c, http.StatusCreated, mapped.Version, dto.MapPlatformToDto(mapped))
 }
 
 func updatePlatform(c *gin.Context) {
@@ -56,18 +56,23 @@ func updatePlatform(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 	var model dto.PlatformEditDto
 	if c.MustBindWith(&model, binding.JSON) != nil {
 		return
 	}
 
 	mapped := dto.MapPlatformEditDtoToObject(id, &model)
+	mapped.Version = version
 	if err = platforms.UpdatePlatform(mapped, auth.GetUserId(c)); err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapPlatformToDto(mapped))
+	respondWithETag(c, http.StatusOK, mapped.Version, dto.MapPlatformToDto(mapped))
 }
 
 func deletePlatform(c *gin.Context) {
@@ -75,8 +80,22 @@ func deletePlatform(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	var query struct {
+		ReassignTo uuid.UUID `form:"reassignTo"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
 
-	err = platforms.DeletePlatform(id, auth.GetUserId(c))
+	if query.ReassignTo != uuid.Nil {
+		_, err = platforms.MergePlatform(id, version, query.ReassignTo, auth.GetUserId(c))
+	} else {
+		err = platforms.DeletePlatform(id, version, auth.GetUserId(c))
+	}
 	if err != nil {
 		handleError(c, err)
 		return
@@ -84,3 +103,26 @@ func deletePlatform(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+func mergePlatform(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	targetId, err := parseUuidFromPathParam(c, "targetId")
+	if err != nil {
+		return
+	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	moved, err := platforms.MergePlatform(id, version, targetId, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PlatformMergeResultDto{GamesMoved: moved})
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/playsessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func getPlaySessions(c *gin.Context) {
+	playthroughId, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	list, err := playsessions.GetPlaySessions(playthroughId, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapPlaySessionToDto))
+}
+
+func getPlaySession(c *gin.Context) {
+	playthroughId, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	id, err := parseUuidFromPathParam(c, "sessionId")
+	if err != nil {
+		return
+	}
+
+	item, err := playsessions.GetPlaySession(id, playthroughId, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapPlaySessionToDto(item))
+}
+
+func createPlaySession(c *gin.Context) {
+	playthroughId, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	var model dto.PlaySessionEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	mapped := dto.MapPlaySessionEditDtoToObject(uuid.Nil, playthroughId, &model)
+	if err = playsessions.CreatePlaySession(mapped, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapPlaySessionToDto(mapped))
+}
+
+func updatePlaySession(c *gin.Context) {
+	playthroughId, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	id, err := parseUuidFromPathParam(c, "sessionId")
+	if err != nil {
+		return
+	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+	var model dto.PlaySessionEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	mapped := dto.MapPlaySessionEditDtoToObject(id, playthroughId, &model)
+	if err = playsessions.UpdatePlaySession(mapped, version, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapPlaySessionToDto(mapped))
+}
+
+func deletePlaySession(c *gin.Context) {
+	playthroughId, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	id, err := parseUuidFromPathParam(c, "sessionId")
+	if err != nil {
+		return
+	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err = playsessions.DeletePlaySession(id, playthroughId, version, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
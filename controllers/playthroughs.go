@@ -40,7 +40,7 @@ func getPlaythrough(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapPlaythroughToDto(item))
+	respondWithETag(c, http.StatusOK, item.Version, dto.MapPlaythroughToDto(item))
 }
 
 func createPlaythrough(c *gin.Context) {
@@ -55,7 +55,7 @@ func createPlaythrough(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.MapPlaythroughToDto(mapped))
+	respondWithETag(c, http.StatusCreated, mapped.Version, dto.MapPlaythroughToDto(mapped))
 }
 
 func updatePlaythrough(c *gin.Context) {
@@ -63,18 +63,23 @@ func updatePlaythrough(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 	var model dto.PlaythroughEditDto
 	if c.MustBindWith(&model, binding.JSON) != nil {
 		return
 	}
 
 	mapped := dto.MapPlaythroughEditDtoToObject(id, &model)
+	mapped.Version = version
 	if err = playthroughs.UpdatePlaythrough(mapped, auth.GetUserId(c)); err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MapPlaythroughToDto(mapped))
+	respondWithETag(c, http.StatusOK, mapped.Version, dto.MapPlaythroughToDto(mapped))
 }
 
 func deletePlaythrough(c *gin.Context) {
@@ -82,8 +87,12 @@ func deletePlaythrough(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 
-	err = playthroughs.DeletePlaythrough(id, auth.GetUserId(c))
+	err = playthroughs.DeletePlaythrough(id, version, auth.GetUserId(c))
 	if err != nil {
 		handleError(c, err)
 		return
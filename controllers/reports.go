@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"bytes"
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/reports"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// getYearReport returns the year report as JSON, or as an HTML page if requested with ?format=html or the Accept header.
+func getYearReport(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil || year < 1970 || year > 9999 {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	report, err := reports.GetYearReport(year, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if c.Query("format") == "html" || c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		var page bytes.Buffer
+		if err = reports.RenderYearReport(&page, report); err != nil {
+			log.Warnf("Failed to render year report: %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapYearReportToDto(report))
+}
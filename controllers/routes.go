@@ -13,6 +13,26 @@ func SetRoutes(r *gin.RouterGroup) {
 	auths.GET("/logout", logout)
 	auths.GET("/providers", getProviders)
 	auths.GET("/me", getUser)
+	auths.GET("/me/export", auth.GetLoginRequiredMiddleware(), exportUser)
+	auths.DELETE("/me", auth.GetSessionRequiredMiddleware(), deleteUser)
+
+	identities := auths.Group("/identities")
+	identities.Use(auth.GetSessionRequiredMiddleware())
+	identities.GET("", getIdentities)
+	identities.GET("/link", linkIdentity)
+	identities.DELETE("/:id", unlinkIdentity)
+
+	sessions := auths.Group("/sessions")
+	sessions.Use(auth.GetSessionRequiredMiddleware())
+	sessions.GET("", getSessions)
+	sessions.DELETE("", revokeOtherSessions)
+	sessions.DELETE("/:id", revokeSession)
+
+	tokens := auths.Group("/tokens")
+	tokens.Use(auth.GetSessionRequiredMiddleware())
+	tokens.GET("", getTokens)
+	tokens.POST("", createToken)
+	tokens.DELETE("/:id", revokeToken)
 
 	// platforms API
 	platforms := r.Group("/platforms")
@@ -22,16 +42,35 @@ func SetRoutes(r *gin.RouterGroup) {
 	platforms.POST("", createPlatform)
 	platforms.PUT("/:id", updatePlatform)
 	platforms.DELETE("/:id", deletePlatform)
+	platforms.POST("/:id/merge-into/:targetId", mergePlatform)
+
+	// platform catalog API
+	platformCatalog := r.Group("/catalog/platforms")
+	platformCatalog.Use(auth.GetLoginRequiredMiddleware())
+	platformCatalog.GET("", getCatalogPlatforms)
+	platformCatalog.POST("/:id/adopt", adoptCatalogPlatform)
 
 	// games API
 	games := r.Group("/games")
 	games.Use(auth.GetLoginRequiredMiddleware())
 	games.GET("", getGames)
+	games.GET("/upcoming", getUpcomingGames)
 	games.GET("/:id", getGame)
 	games.GET("/:id/playthroughs", getPlaythroughsForGame)
 	games.POST("", createGame)
 	games.PUT("/:id", updateGame)
 	games.DELETE("/:id", deleteGame)
+	games.PUT("/:id/tags/:tagId", attachTagToGame)
+	games.DELETE("/:id/tags/:tagId", detachTagFromGame)
+
+	// tags API
+	tags := r.Group("/tags")
+	tags.Use(auth.GetLoginRequiredMiddleware())
+	tags.GET("", getTags)
+	tags.GET("/:id", getTag)
+	tags.POST("", createTag)
+	tags.PUT("/:id", updateTag)
+	tags.DELETE("/:id", deleteTag)
 
 	// playthroughs API
 	playthroughs := r.Group("/playthroughs")
@@ -41,4 +80,65 @@ func SetRoutes(r *gin.RouterGroup) {
 	playthroughs.POST("", createPlaythrough)
 	playthroughs.PUT("/:id", updatePlaythrough)
 	playthroughs.DELETE("/:id", deletePlaythrough)
+	playthroughs.GET("/:id/sessions", getPlaySessions)
+	playthroughs.GET("/:id/sessions/:sessionId", getPlaySession)
+	playthroughs.POST("/:id/sessions", createPlaySession)
+	playthroughs.PUT("/:id/sessions/:sessionId", updatePlaySession)
+	playthroughs.DELETE("/:id/sessions/:sessionId", deletePlaySession)
+	playthroughs.GET("/:id/timer", getTimer)
+	playthroughs.POST("/:id/timer/start", startTimer)
+	playthroughs.POST("/:id/timer/stop", stopTimer)
+
+	// stats API
+	stats := r.Group("/stats")
+	stats.Use(auth.GetLoginRequiredMiddleware())
+	stats.GET("", getStats)
+
+	// reports API
+	reports := r.Group("/reports")
+	reports.Use(auth.GetLoginRequiredMiddleware())
+	reports.GET("/year/:year", getYearReport)
+
+	// trash API
+	trashItems := r.Group("/trash")
+	trashItems.Use(auth.GetLoginRequiredMiddleware())
+	trashItems.GET("", getTrash)
+	trashItems.POST("/:type/:id/restore", restoreFromTrash)
+
+	// history API
+	history := r.Group("/history")
+	history.Use(auth.GetLoginRequiredMiddleware())
+	history.GET("", getHistory)
+
+	// exports API
+	exports := r.Group("/export")
+	exports.Use(auth.GetLoginRequiredMiddleware())
+	exports.GET("/games.csv", exportGamesCsv)
+	exports.GET("/playthroughs.csv", exportPlaythroughsCsv)
+
+	// calendar API
+	// the feed itself is authenticated with its secret, as calendar clients cannot log in
+	calendarFeed := r.Group("/calendar")
+	calendarFeed.GET("", auth.GetSessionRequiredMiddleware(), getCalendarFeed)
+	calendarFeed.POST("", auth.GetSessionRequiredMiddleware(), regenerateCalendarFeed)
+	calendarFeed.DELETE("", auth.GetSessionRequiredMiddleware(), deleteCalendarFeed)
+	calendarFeed.GET("/:token", getCalendar)
+
+	// webhooks API
+	webhookList := r.Group("/webhooks")
+	webhookList.Use(auth.GetLoginRequiredMiddleware())
+	webhookList.GET("", getWebhooks)
+	webhookList.GET("/:id", getWebhook)
+	webhookList.POST("", createWebhook)
+	webhookList.PUT("/:id", updateWebhook)
+	webhookList.DELETE("/:id", deleteWebhook)
+	webhookList.GET("/:id/deliveries", getWebhookDeliveries)
+
+	// imports API
+	imports := r.Group("/import")
+	imports.Use(auth.GetLoginRequiredMiddleware())
+	imports.POST("/archive", importArchive)
+	imports.POST("/csv", importCsv)
+	imports.POST("/retroarch", importRetroArch)
+	imports.POST("/playnite", importPlaynite)
 }
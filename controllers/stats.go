@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/stats"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"time"
+)
+
+func getStats(c *gin.Context) {
+	var query struct {
+		From *time.Time `form:"from" time_format:"2006-01-02"`
+		// To is inclusive, the whole day is part of the range
+		To *time.Time `form:"to" time_format:"2006-01-02"`
+	}
+	if c.MustBindWith(&query, binding.Query) != nil {
+		return
+	}
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: "the end of the range cannot be before its start"})
+		return
+	}
+	if query.To != nil {
+		end := query.To.AddDate(0, 0, 1)
+		query.To = &end
+	}
+
+	result, err := stats.GetStats(utils.MakeNullTime(query.From), utils.MakeNullTime(query.To), auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapStatsToDto(result))
+}
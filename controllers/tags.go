@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/tags"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func getTags(c *gin.Context) {
+	list, err := tags.GetTags(auth.GetUserId(c))
+
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapTagToDto))
+}
+
+func getTag(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	item, err := tags.GetTag(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTagToDto(item))
+}
+
+func createTag(c *gin.Context) {
+	var model dto.TagEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	mapped := dto.MapTagEditDtoToObject(uuid.Nil, &model)
+	if err := tags.CreateTag(mapped, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapTagToDto(mapped))
+}
+
+func updateTag(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	var model dto.TagEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	mapped := dto.MapTagEditDtoToObject(id, &model)
+	if err = tags.UpdateTag(mapped, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTagToDto(mapped))
+}
+
+func deleteTag(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	err = tags.DeleteTag(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func attachTagToGame(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	tagId, err := parseUuidFromPathParam(c, "tagId")
+	if err != nil {
+		return
+	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err = tags.AttachTag(id, tagId, version, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func detachTagFromGame(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	tagId, err := parseUuidFromPathParam(c, "tagId")
+	if err != nil {
+		return
+	}
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	if err = tags.DetachTag(id, tagId, version, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/timers"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func getTimer(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	timer, err := timers.GetTimer(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTimerToDto(timer))
+}
+
+func startTimer(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	timer, err := timers.StartTimer(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapTimerToDto(timer))
+}
+
+func stopTimer(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	timer, err := timers.StopTimer(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapTimerToDto(timer))
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/tokens"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+func getTokens(c *gin.Context) {
+	list, err := tokens.GetTokens(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapTokenToDto))
+}
+
+func createToken(c *gin.Context) {
+	var model dto.TokenEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	mapped := dto.MapTokenEditDtoToObject(&model)
+	secret, err := tokens.CreateToken(mapped, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapCreatedTokenToDto(mapped, secret))
+}
+
+func revokeToken(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	if err = tokens.RevokeToken(id, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/trash"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func getTrash(c *gin.Context) {
+	list, err := trash.GetItems(auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapTrashItemToDto))
+}
+
+func restoreFromTrash(c *gin.Context) {
+	itemType := trash.ItemType(c.Param("type"))
+	if itemType != trash.ItemGame && itemType != trash.ItemPlatform && itemType != trash.ItemPlaythrough {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	if err = trash.Restore(itemType, id, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -4,7 +4,11 @@ import (
 	"errors"
 	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/timers"
 	"github.com/KowalskiPiotr98/ludivault/users"
+	"github.com/KowalskiPiotr98/ludivault/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/markbates/goth"
@@ -17,14 +21,39 @@ func handleError(c *gin.Context, err error) {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
+	if errors.Is(err, platforms.NameAlreadyUsedErr) || errors.Is(err, platforms.ShortNameAlreadyUsedErr) {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorDto{Error: err.Error()})
+		return
+	}
 	if errors.Is(err, operations.Errors.DataAlreadyExistErr) {
 		c.AbortWithStatus(http.StatusConflict)
 		return
 	}
+	var inUseErr *platforms.InUseError
+	if errors.As(err, &inUseErr) {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.MapPlatformInUseErrorToDto(inUseErr))
+		return
+	}
 	if errors.Is(err, operations.Errors.DataUsedErr) {
 		c.AbortWithStatus(http.StatusConflict)
 		return
 	}
+	if errors.Is(err, platforms.MergeIntoSelfErr) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, timers.TimerAlreadyRunningErr) {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorDto{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, utils.VersionMismatchErr) {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, dto.ErrorDto{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, users.LastIdentityErr) {
+		c.AbortWithStatus(http.StatusConflict)
+		return
+	}
 	if errors.Is(err, operations.Errors.RowNumberUnexpectedErr) {
 		c.AbortWithStatus(http.StatusBadRequest)
 		return
@@ -34,7 +63,11 @@ func handleError(c *gin.Context, err error) {
 }
 
 func parseUuidFromPath(c *gin.Context) (uuid.UUID, error) {
-	value := c.Param("id")
+	return parseUuidFromPathParam(c, "id")
+}
+
+func parseUuidFromPathParam(c *gin.Context, name string) (uuid.UUID, error) {
+	value := c.Param(name)
 	id, err := uuid.Parse(value)
 	if err != nil {
 		c.AbortWithStatus(http.StatusBadRequest)
@@ -43,15 +76,37 @@ func parseUuidFromPath(c *gin.Context) (uuid.UUID, error) {
 	return id, nil
 }
 
+func parseUuids(c *gin.Context, values []string) ([]uuid.UUID, error) {
+	result := make([]uuid.UUID, len(values))
+	for i, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return nil, err
+		}
+		result[i] = id
+	}
+	return result, nil
+}
+
 func initUserSession(user *goth.User, c *gin.Context) error {
-	localUser := users.NewFromProvider(user)
-	if err := users.GetOrCreate(localUser); err != nil {
+	identity := users.NewFromProvider(user)
+	if err := users.GetOrCreate(identity); err != nil {
 		log.Warnf("Failed to initialise user session: %v", err)
 		return err
 	}
-	if err := auth.StoreUserInSession(c, localUser.Id); err != nil {
+	if err := auth.StoreUserInSession(c, identity.UserId); err != nil {
 		log.Warnf("Failed to initialise user session: %v", err)
 		return err
 	}
 	return nil
 }
+
+func linkUserIdentity(user *goth.User, c *gin.Context) error {
+	identity := users.NewFromProvider(user)
+	if err := users.LinkIdentity(identity, auth.GetUserId(c)); err != nil {
+		log.Warnf("Failed to link user identity: %v", err)
+		return err
+	}
+	return nil
+}
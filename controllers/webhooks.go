@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/auth"
+	"github.com/KowalskiPiotr98/ludivault/controllers/dto"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func getWebhooks(c *gin.Context) {
+	list, err := webhooks.GetWebhooks(auth.GetUserId(c))
+
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapWebhookToDto))
+}
+
+func getWebhook(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	item, err := webhooks.GetWebhook(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapWebhookToDto(item))
+}
+
+func createWebhook(c *gin.Context) {
+	var model dto.WebhookEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+	if model.Secret == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorDto{Error: "secret is missing"})
+		return
+	}
+
+	mapped := dto.MapWebhookEditDtoToObject(uuid.Nil, &model)
+	if err := webhooks.CreateWebhook(mapped, auth.GetUserId(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.MapWebhookToDto(mapped))
+}
+
+func updateWebhook(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	var model dto.WebhookEditDto
+	if c.MustBindWith(&model, binding.JSON) != nil {
+		return
+	}
+
+	userId := auth.GetUserId(c)
+	mapped := dto.MapWebhookEditDtoToObject(id, &model)
+	if err = webhooks.UpdateWebhook(mapped, userId); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	updated, err := webhooks.GetWebhook(id, userId)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapWebhookToDto(updated))
+}
+
+func deleteWebhook(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+
+	err = webhooks.DeleteWebhook(id, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func getWebhookDeliveries(c *gin.Context) {
+	id, err := parseUuidFromPath(c)
+	if err != nil {
+		return
+	}
+	model := struct {
+		Limit  int `form:"limit" binding:"min=1,max=100"`
+		Offset int `form:"offset" binding:"min=0"`
+	}{
+		Limit:  20,
+		Offset: 0,
+	}
+	if err = c.MustBindWith(&model, binding.Query); err != nil {
+		return
+	}
+
+	list, err := webhooks.GetDeliveries(id, model.Offset, model.Limit, auth.GetUserId(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MapMany(list, dto.MapWebhookDeliveryToDto))
+}
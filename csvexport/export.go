@@ -0,0 +1,123 @@
+package csvexport
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	// flushInterval is the number of rows written before the output is flushed to the client.
+	flushInterval = 100
+)
+
+var (
+	gamesHeader        = []string{"title", "platform", "owned", "released", "release_date", "tags"}
+	playthroughsHeader = []string{"title", "platform", "playthrough_start", "playthrough_end", "playthrough_status", "playthrough_runtime"}
+)
+
+// ExportGames writes the games of the user matching the filter to w as CSV.
+// Rows are written as they are read from the database, so that the whole library is never loaded into memory.
+func ExportGames(w io.Writer, filter games.Filter, userId uuid.UUID) error {
+	condition, args := filter.Condition([]interface{}{userId})
+	query := `select games.title, p.name, games.owned, games.released, games.release_date, games.release_date_precision,
+			coalesce((select string_agg(t.name, '; ' order by t.name) from games_tags gt join tags t on t.id = gt.tag_id where gt.game_id = games.id), '')
+		from games
+		join platforms p on p.id = games.platform_id
+		where games.user_id = $1 and games.deleted_at is null ` + condition + `
+		order by games.title, p.name`
+
+	return export(w, gamesHeader, query, args, func(rows gotabase.Rows) ([]string, error) {
+		var title, platform, tags string
+		var owned, released bool
+		var releaseDate sql.NullTime
+		var precision games.ReleaseDatePrecision
+		if err := rows.Scan(&title, &platform, &owned, &released, &releaseDate, &precision, &tags); err != nil {
+			return nil, err
+		}
+		return []string{title, platform, strconv.FormatBool(owned), strconv.FormatBool(released), games.FormatReleaseDate(releaseDate, precision), tags}, nil
+	})
+}
+
+// ExportPlaythroughs writes playthroughs of the games of the user matching the filter to w as CSV.
+// The runtime is derived from play sessions when there are any, the same way as when reading a single playthrough.
+func ExportPlaythroughs(w io.Writer, filter games.Filter, userId uuid.UUID) error {
+	condition, args := filter.Condition([]interface{}{userId})
+	query := `select games.title, p.name, pt.start_date, pt.end_date, pt.status, coalesce(s.runtime, pt.runtime_minutes)
+		from playthroughs pt
+		join games on games.id = pt.game_id
+		join platforms p on p.id = games.platform_id
+		cross join lateral (
+			select ` + playthroughs.SessionRuntime + ` runtime
+			from play_sessions where playthrough_id = pt.id
+		) s
+		where games.user_id = $1 and pt.deleted_at is null and games.deleted_at is null ` + condition + `
+		order by games.title, pt.start_date`
+
+	return export(w, playthroughsHeader, query, args, func(rows gotabase.Rows) ([]string, error) {
+		var title, platform string
+		var start time.Time
+		var end sql.NullTime
+		var status playthroughs.PlaythroughStatus
+		var runtime sql.NullInt32
+		if err := rows.Scan(&title, &platform, &start, &end, &status, &runtime); err != nil {
+			return nil, err
+		}
+		return []string{title, platform, start.UTC().Format(dateFormat), formatDate(end), status.String(), formatInt(runtime)}, nil
+	})
+}
+
+func export(w io.Writer, header []string, query string, args []interface{}, scan func(rows gotabase.Rows) ([]string, error)) error {
+	rows, err := getDatabase().QueryRows(query, args...)
+	if err != nil {
+		return operations.Errors.HandleError(err)
+	}
+	defer rows.Close()
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write(header); err != nil {
+		return err
+	}
+
+	for count := 1; rows.Next(); count++ {
+		record, err := scan(rows)
+		if err != nil {
+			return operations.Errors.HandleError(err)
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+		if count%flushInterval == 0 {
+			writer.Flush()
+		}
+	}
+	if err = utils.RowsErr(rows); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatDate(value sql.NullTime) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.Time.UTC().Format(dateFormat)
+}
+
+func formatInt(value sql.NullInt32) string {
+	if !value.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(value.Int32))
+}
@@ -0,0 +1,96 @@
+package csvexport
+
+import (
+	"bytes"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func makePlatform(name string, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into platforms (id, name, short_name, user_id) values ($1, $2, left($2, 5), $3)`
+	_, err := getDatabase().Exec(query, id, name, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makeGame(title string, platformId uuid.UUID, owned bool, releaseDate string, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into games (id, title, platform_id, owned, release_date, released, user_id) values ($1, $2, $3, $4, nullif($5, '')::timestamptz, true, $6)`
+	_, err := getDatabase().Exec(query, id, title, platformId, owned, releaseDate, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func TestExportGames(t *testing.T) {
+	t.Run("Games exported", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform("PC", userId)
+		gameId := makeGame("Hades, Deluxe", platformId, true, "2020-09-17T00:00:00Z", userId)
+		makeGame("Celeste", platformId, false, "", userId)
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		makeGame("Other", makePlatform("PC", otherUserId), true, "", otherUserId)
+		_, err := getDatabase().Exec(`with t as (insert into tags (name, user_id) values ('roguelike', $2) returning id) insert into games_tags (game_id, tag_id) select $1, id from t`, gameId, userId)
+		tests.PanicOnErr(err)
+		var buffer bytes.Buffer
+
+		err = ExportGames(&buffer, games.Filter{}, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "title,platform,owned,released,release_date,tags\n"+
+			"Celeste,PC,false,true,,\n"+
+			"\"Hades, Deluxe\",PC,true,true,2020-09-17,roguelike\n", buffer.String())
+	})
+
+	t.Run("Filters applied", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform("PC", userId)
+		makeGame("Hades", platformId, true, "", userId)
+		makeGame("Celeste", platformId, false, "", userId)
+		owned := true
+		var buffer bytes.Buffer
+
+		err := ExportGames(&buffer, games.Filter{Owned: &owned}, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "title,platform,owned,released,release_date,tags\nHades,PC,true,true,,\n", buffer.String())
+	})
+}
+
+func TestExportPlaythroughs(t *testing.T) {
+	t.Run("Playthroughs exported with status names", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		gameId := makeGame("Celeste", makePlatform("PC", userId), true, "", userId)
+		_, err := getDatabase().Exec(`insert into playthroughs (game_id, start_date, end_date, status, runtime_minutes) values ($1, '2024-01-01T10:00:00Z', '2024-02-01T10:00:00Z', 1, 600), ($1, '2024-03-01T10:00:00Z', null, 0, null)`, gameId)
+		tests.PanicOnErr(err)
+		var buffer bytes.Buffer
+
+		err = ExportPlaythroughs(&buffer, games.Filter{}, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "title,platform,playthrough_start,playthrough_end,playthrough_status,playthrough_runtime\n"+
+			"Celeste,PC,2024-01-01,2024-02-01,Completed,600\n"+
+			"Celeste,PC,2024-03-01,,In progress,\n", buffer.String())
+	})
+
+	t.Run("Filters applied", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform("PC", userId)
+		_, err := getDatabase().Exec(`insert into playthroughs (game_id, start_date, status) values ($1, now(), 0), ($2, now(), 0)`, makeGame("Celeste", platformId, true, "", userId), makeGame("Hades", platformId, true, "", userId))
+		tests.PanicOnErr(err)
+		var buffer bytes.Buffer
+
+		err = ExportPlaythroughs(&buffer, games.Filter{Title: "hade"}, userId)
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), "Hades")
+		assert.NotContains(t, buffer.String(), "Celeste")
+	})
+}
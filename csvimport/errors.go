@@ -0,0 +1,7 @@
+package csvimport
+
+import "errors"
+
+var (
+	InvalidFileErr = errors.New("csv file contains invalid data")
+)
@@ -0,0 +1,93 @@
+package csvimport
+
+import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"io"
+)
+
+// Import creates games, and optionally their playthroughs, from a CSV file in a single transaction.
+//
+// Platforms are matched by name or short name, and the ones that do not exist yet are created.
+// If any row is invalid, nothing is created and InvalidFileErr is returned along with the errors found.
+//
+// In dry-run mode all changes are made and then rolled back, so that the result shows what would be created.
+func Import(reader io.Reader, dryRun bool, userId uuid.UUID) (*Result, error) {
+	result := &Result{
+		DryRun:           dryRun,
+		PlatformsCreated: make([]string, 0),
+		Rows:             make([]*RowPreview, 0),
+		Errors:           make([]*RowError, 0),
+	}
+
+	rows := parse(reader, result)
+	if len(result.Errors) > 0 {
+		return result, InvalidFileErr
+	}
+
+	err := utils.RunInTransactionWithDryRun(dryRun, func(tx gotabase.Connector) error {
+		return importRows(tx, rows, userId, result)
+	})
+	if errors.Is(err, InvalidFileErr) {
+		// the transaction was rolled back, so nothing was created after all
+		result.PlatformsCreated = make([]string, 0)
+		result.GamesCreated = 0
+		result.PlaythroughsCreated = 0
+		result.Rows = make([]*RowPreview, 0)
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func importRows(tx gotabase.Connector, rows []*row, userId uuid.UUID, result *Result) error {
+	existing, err := platforms.GetPlatformsTx(tx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		platform := platforms.FindByName(existing, row.platform)
+		newPlatform := platform == nil
+		if newPlatform {
+			if platform, err = platforms.CreateByNameTx(tx, existing, row.platform, userId); err != nil {
+				result.addError(row.line, "failed to create platform %q: %v", row.platform, err)
+				return InvalidFileErr
+			}
+			existing = append(existing, platform)
+			result.PlatformsCreated = append(result.PlatformsCreated, platform.Name)
+		}
+
+		row.game.PlatformId = platform.Id
+		if err = games.CreateGameTx(tx, row.game, userId); err != nil {
+			return err
+		}
+		result.GamesCreated++
+
+		if row.playthrough != nil {
+			row.playthrough.GameId = row.game.Id
+			if err = playthroughs.CreatePlaythroughTx(tx, row.playthrough, userId); err != nil {
+				return err
+			}
+			result.PlaythroughsCreated++
+		}
+
+		result.Rows = append(result.Rows, &RowPreview{
+			Line:           row.line,
+			Title:          row.game.Title,
+			Platform:       platform.Name,
+			NewPlatform:    newPlatform,
+			HasPlaythrough: row.playthrough != nil,
+		})
+	}
+
+	return nil
+}
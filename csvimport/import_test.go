@@ -0,0 +1,84 @@
+package csvimport
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testFile = "title,platform,owned,playthrough_start,playthrough_end\n" +
+	"Celeste,pc,yes,2024-01-01,2024-02-01\n" +
+	"Hades,Nintendo Switch,no,,\n" +
+	"Metroid,Nintendo Switch 2,no,,\n"
+
+func TestImport(t *testing.T) {
+	t.Run("Rows imported", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		existing := &platforms.Platform{Name: "PC", ShortName: "PC"}
+		tests.PanicOnErr(platforms.CreatePlatform(existing, userId))
+
+		result, err := Import(strings.NewReader(testFile), false, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Nintendo Switch", "Nintendo Switch 2"}, result.PlatformsCreated)
+		assert.Equal(t, 3, result.GamesCreated)
+		assert.Equal(t, 1, result.PlaythroughsCreated)
+		assert.Equal(t, 2, result.Rows[0].Line)
+		assert.False(t, result.Rows[0].NewPlatform)
+		assert.True(t, result.Rows[1].NewPlatform)
+		userPlatforms, err := platforms.GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userPlatforms, 3)
+		shortNames := make([]string, 0)
+		for _, platform := range userPlatforms {
+			shortNames = append(shortNames, platform.ShortName)
+		}
+		assert.ElementsMatch(t, []string{"PC", "Ninte", "Nint2"}, shortNames)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 3)
+		userPlaythroughs, err := playthroughs.GetPlaythroughs(uuid.Nil, userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userPlaythroughs, 1)
+		assert.Equal(t, playthroughs.PlaythroughCompleted, userPlaythroughs[0].Status)
+	})
+
+	t.Run("Dry run creates nothing", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := Import(strings.NewReader(testFile), true, userId)
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 3, result.GamesCreated)
+		assert.Len(t, result.PlatformsCreated, 3)
+		assert.Len(t, result.Rows, 3)
+		userPlatforms, err := platforms.GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userPlatforms)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+	})
+
+	t.Run("Invalid rows prevent import", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := Import(strings.NewReader("title,platform,owned\nCeleste,PC,yes\nHades,,no\n"), false, userId)
+
+		assert.Equal(t, InvalidFileErr, err)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, 3, result.Errors[0].Line)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+	})
+}
@@ -0,0 +1,48 @@
+package csvimport
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+)
+
+// Result summarises the changes made, or that would be made in dry-run mode, by Import.
+type Result struct {
+	DryRun              bool
+	PlatformsCreated    []string
+	GamesCreated        int
+	PlaythroughsCreated int
+	Rows                []*RowPreview
+	Errors              []*RowError
+}
+
+// RowPreview describes what was created from a single row of the file.
+type RowPreview struct {
+	Line           int
+	Title          string
+	Platform       string
+	NewPlatform    bool
+	HasPlaythrough bool
+}
+
+// RowError describes a problem with a single row of the file.
+// Line is the 1-based line number of the row in the file.
+type RowError struct {
+	Line    int
+	Message string
+}
+
+func (r *Result) addError(line int, format string, args ...any) {
+	r.Errors = append(r.Errors, &RowError{
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// row is a single parsed entry of the file.
+type row struct {
+	line        int
+	platform    string
+	game        *games.Game
+	playthrough *playthroughs.Playthrough
+}
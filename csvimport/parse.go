@@ -0,0 +1,228 @@
+package csvimport
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	columnTitle              = "title"
+	columnPlatform           = "platform"
+	columnOwned              = "owned"
+	columnReleased           = "released"
+	columnReleaseDate        = "release_date"
+	columnPlaythroughStart   = "playthrough_start"
+	columnPlaythroughEnd     = "playthrough_end"
+	columnPlaythroughStatus  = "playthrough_status"
+	columnPlaythroughRuntime = "playthrough_runtime"
+
+	dateFormat = "2006-01-02"
+)
+
+var knownColumns = []string{
+	columnTitle, columnPlatform, columnOwned, columnReleased, columnReleaseDate,
+	columnPlaythroughStart, columnPlaythroughEnd, columnPlaythroughStatus, columnPlaythroughRuntime,
+}
+
+// parse reads all rows of the file, reporting any invalid values in the result.
+//
+// The first line must be a header naming the columns, in any order.
+// Only the title and platform columns are required, playthrough columns are only used if the start date is set.
+func parse(reader io.Reader, result *Result) []*row {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		result.addError(1, "file is empty")
+		return nil
+	}
+	if err != nil {
+		result.addError(errorLine(err, 1), "failed to read header: %v", err)
+		return nil
+	}
+	columns, ok := parseHeader(header, result)
+	if !ok {
+		return nil
+	}
+
+	rows := make([]*row, 0)
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			result.addError(errorLine(err, 0), "failed to read row: %v", err)
+			return nil
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		values := make(map[string]string, len(columns))
+		for i, value := range record {
+			if name, ok := columns[i]; ok {
+				values[name] = strings.TrimSpace(value)
+			}
+		}
+		if parsed := parseRow(line, values, result); parsed != nil {
+			rows = append(rows, parsed)
+		}
+	}
+
+	if len(rows) == 0 && len(result.Errors) == 0 {
+		result.addError(1, "file contains no rows")
+	}
+	return rows
+}
+
+func parseHeader(header []string, result *Result) (map[int]string, bool) {
+	columns := make(map[int]string, len(header))
+	found := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		name = strings.ReplaceAll(name, " ", "_")
+		if !isKnownColumn(name) {
+			result.addError(1, "unknown column %q", header[i])
+			continue
+		}
+		if found[name] {
+			result.addError(1, "column %q is duplicated", header[i])
+			continue
+		}
+		found[name] = true
+		columns[i] = name
+	}
+
+	for _, required := range []string{columnTitle, columnPlatform} {
+		if !found[required] {
+			result.addError(1, "required column %q is missing", required)
+		}
+	}
+	return columns, len(result.Errors) == 0
+}
+
+func isKnownColumn(name string) bool {
+	for _, known := range knownColumns {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRow(line int, values map[string]string, result *Result) *row {
+	errorCount := len(result.Errors)
+	parsed := &row{
+		line:     line,
+		platform: values[columnPlatform],
+		game:     &games.Game{Title: values[columnTitle]},
+	}
+
+	if parsed.game.Title == "" || utf8.RuneCountInString(parsed.game.Title) > 500 {
+		result.addError(line, "title must be between 1 and 500 characters long")
+	}
+	if parsed.platform == "" || utf8.RuneCountInString(parsed.platform) > 200 {
+		result.addError(line, "platform must be between 1 and 200 characters long")
+	}
+
+	var ok bool
+	if parsed.game.Owned, ok = parseBool(values[columnOwned]); !ok {
+		result.addError(line, "owned must be a boolean value, got %q", values[columnOwned])
+	}
+	if parsed.game.ReleaseDate, parsed.game.ReleaseDatePrecision, ok = games.ParseReleaseDate(values[columnReleaseDate]); !ok {
+		result.addError(line, "release date must be formatted as YYYY-MM-DD, YYYY-MM, YYYY-Qn, YYYY or TBA, got %q", values[columnReleaseDate])
+	}
+	if values[columnReleased] == "" {
+		// when not specified, the game is considered released if its release date has passed
+		parsed.game.Released = parsed.game.ReleaseDatePassed(time.Now())
+	} else if parsed.game.Released, ok = parseBool(values[columnReleased]); !ok {
+		result.addError(line, "released must be a boolean value, got %q", values[columnReleased])
+	}
+
+	parsed.playthrough = parsePlaythrough(line, values, result)
+
+	if len(result.Errors) != errorCount {
+		return nil
+	}
+	return parsed
+}
+
+func parsePlaythrough(line int, values map[string]string, result *Result) *playthroughs.Playthrough {
+	start, ok := parseDate(values[columnPlaythroughStart])
+	if !ok {
+		result.addError(line, "playthrough start must be a date formatted as YYYY-MM-DD, got %q", values[columnPlaythroughStart])
+		return nil
+	}
+	if !start.Valid {
+		if values[columnPlaythroughEnd] != "" || values[columnPlaythroughStatus] != "" || values[columnPlaythroughRuntime] != "" {
+			result.addError(line, "playthrough start is required when other playthrough columns are set")
+		}
+		return nil
+	}
+
+	playthrough := &playthroughs.Playthrough{StartDate: start.Time}
+	if playthrough.EndDate, ok = parseDate(values[columnPlaythroughEnd]); !ok {
+		result.addError(line, "playthrough end must be a date formatted as YYYY-MM-DD, got %q", values[columnPlaythroughEnd])
+	} else if playthrough.EndDate.Valid && playthrough.EndDate.Time.Before(playthrough.StartDate) {
+		result.addError(line, "playthrough end cannot be before its start")
+	}
+
+	if status := values[columnPlaythroughStatus]; status == "" {
+		// playthroughs with an end date are considered completed, unless stated otherwise
+		if playthrough.EndDate.Valid {
+			playthrough.Status = playthroughs.PlaythroughCompleted
+		}
+	} else if playthrough.Status, ok = playthroughs.ParsePlaythroughStatus(status); !ok {
+		result.addError(line, "playthrough status %q is not valid", status)
+	}
+
+	if runtime := values[columnPlaythroughRuntime]; runtime != "" {
+		minutes, err := strconv.Atoi(runtime)
+		if err != nil || minutes < 0 {
+			result.addError(line, "playthrough runtime must be a non-negative number of minutes, got %q", runtime)
+		} else {
+			playthrough.Runtime = sql.NullInt32{Valid: true, Int32: int32(minutes)}
+		}
+	}
+
+	return playthrough
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "n", "0":
+		return false, true
+	case "true", "yes", "y", "1":
+		return true, true
+	}
+	return false, false
+}
+
+func parseDate(value string) (sql.NullTime, bool) {
+	if value == "" {
+		return sql.NullTime{}, true
+	}
+	date, err := time.Parse(dateFormat, value)
+	if err != nil {
+		return sql.NullTime{}, false
+	}
+	return sql.NullTime{Valid: true, Time: date}, true
+}
+
+// errorLine returns the line at which the csv reader failed, or the fallback if unknown.
+func errorLine(err error, fallback int) int {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine
+	}
+	return fallback
+}
@@ -0,0 +1,110 @@
+package csvimport
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseString(content string) ([]*row, *Result) {
+	result := &Result{}
+	rows := parse(strings.NewReader(content), result)
+	return rows, result
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Parses games and playthroughs", func(t *testing.T) {
+		content := "Title,Platform,Owned,Release Date,Playthrough_Start,Playthrough_End,Playthrough_Status,Playthrough_Runtime\n" +
+			"Celeste,PC,yes,2018-01-25,2024-01-01,2024-02-01,,600\n" +
+			"\"Hades, Deluxe\",Switch,no,,,,,\n" +
+			"Zelda,Switch,1,2099-01-01,2024-03-01,,dropped,\n"
+
+		rows, result := parseString(content)
+
+		assert.Empty(t, result.Errors)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, 2, rows[0].line)
+		assert.Equal(t, "Celeste", rows[0].game.Title)
+		assert.Equal(t, "PC", rows[0].platform)
+		assert.True(t, rows[0].game.Owned)
+		assert.True(t, rows[0].game.Released)
+		assert.Equal(t, time.Date(2018, 1, 25, 0, 0, 0, 0, time.UTC), rows[0].game.ReleaseDate.Time)
+		assert.Equal(t, playthroughs.PlaythroughCompleted, rows[0].playthrough.Status)
+		assert.Equal(t, int32(600), rows[0].playthrough.Runtime.Int32)
+		assert.Equal(t, "Hades, Deluxe", rows[1].game.Title)
+		assert.False(t, rows[1].game.Owned)
+		assert.False(t, rows[1].game.Released)
+		assert.Nil(t, rows[1].playthrough)
+		assert.False(t, rows[2].game.Released)
+		assert.Equal(t, playthroughs.PlaythroughDropped, rows[2].playthrough.Status)
+		assert.False(t, rows[2].playthrough.Runtime.Valid)
+	})
+
+	t.Run("Reports errors with line numbers", func(t *testing.T) {
+		content := "title,platform,owned,release_date,playthrough_start,playthrough_end,playthrough_status,playthrough_runtime\n" +
+			"Celeste,PC,maybe,,,,,\n" +
+			"Hades,PC,,01/02/2020,,,,\n" +
+			"\"Multi\nline\",PC,,,,,,\n" +
+			",,,,,,,\n" +
+			"Zelda,Switch,,,,,completed,\n" +
+			"Metroid,Switch,,,2024-02-01,2024-01-01,beaten,-5\n"
+
+		rows, result := parseString(content)
+
+		assert.Len(t, rows, 1)
+		lines := make([]int, len(result.Errors))
+		for i, rowError := range result.Errors {
+			lines[i] = rowError.Line
+		}
+		assert.Equal(t, []int{2, 3, 6, 6, 7, 8, 8, 8}, lines)
+	})
+
+	t.Run("Unknown and missing columns reported", func(t *testing.T) {
+		_, result := parseString("title,rating\nCeleste,5\n")
+
+		assert.Len(t, result.Errors, 2)
+		assert.Equal(t, 1, result.Errors[0].Line)
+		assert.Contains(t, result.Errors[0].Message, "rating")
+		assert.Contains(t, result.Errors[1].Message, "platform")
+	})
+
+	t.Run("Partial release dates", func(t *testing.T) {
+		content := "title,platform,release_date\n" +
+			"Hollow Knight: Silksong,PC,TBA\n" +
+			"Metroid Prime 4,Switch,2099-Q4\n" +
+			"Old Game,PC,2001\n"
+
+		rows, result := parseString(content)
+
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, games.ReleaseDateTBA, rows[0].game.ReleaseDatePrecision)
+		assert.False(t, rows[0].game.Released)
+		assert.Equal(t, games.ReleaseDateQuarter, rows[1].game.ReleaseDatePrecision)
+		assert.Equal(t, time.Date(2099, 10, 1, 0, 0, 0, 0, time.UTC), rows[1].game.ReleaseDate.Time)
+		assert.False(t, rows[1].game.Released)
+		assert.True(t, rows[2].game.Released)
+	})
+
+	t.Run("Empty file reported", func(t *testing.T) {
+		_, result := parseString("")
+
+		assert.Len(t, result.Errors, 1)
+	})
+
+	t.Run("Header only reported", func(t *testing.T) {
+		_, result := parseString("title,platform\n")
+
+		assert.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0].Message, "no rows")
+	})
+
+	t.Run("Malformed quoting reported", func(t *testing.T) {
+		_, result := parseString("title,platform\nCeleste,PC\n\"Hades,PC\n")
+
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, 3, result.Errors[0].Line)
+	})
+}
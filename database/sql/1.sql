@@ -0,0 +1,11 @@
+create table sessions (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    created_at timestamp with time zone not null default now(),
+    last_seen_at timestamp with time zone not null default now(),
+    expires_at timestamp with time zone not null,
+    ip_address varchar(45) not null,
+    user_agent varchar(500) not null
+);
+
+create index ix_sessions_user_id on sessions (user_id);
@@ -0,0 +1,11 @@
+-- release date precision values:
+-- 0 - day
+-- 1 - month
+-- 2 - quarter
+-- 3 - year
+-- 4 - to be announced (no release date)
+-- for precisions other than day, the release date holds the first day of the period
+alter table games add column release_date_precision smallint not null default 0 check ( release_date_precision >= 0 and release_date_precision <= 4 );
+alter table games add constraint ck_games_release_date_tba check ( release_date_precision <> 4 or release_date is null );
+
+create index ix_games_upcoming on games (user_id, release_date) where not released;
@@ -0,0 +1,7 @@
+-- each user can have a single calendar feed, accessed with a secret token instead of the session cookie
+create table calendar_feeds (
+    user_id uuid primary key references users(id) on delete cascade,
+    token_hash char(64) not null constraint ix_calendar_feeds_token_hash unique,
+    created_at timestamp with time zone not null default now(),
+    last_used_at timestamp with time zone null
+);
@@ -0,0 +1,9 @@
+-- last run of each background job, shared by all instances of the application
+create table job_runs (
+    name varchar(100) primary key,
+    started_at timestamp with time zone not null,
+    finished_at timestamp with time zone not null,
+    succeeded boolean not null,
+    -- summary of the changes made by the job, or the error it failed with
+    message text not null default ''
+);
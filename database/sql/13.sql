@@ -0,0 +1,32 @@
+create table webhooks (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    url varchar(2000) not null,
+    -- the secret is used to sign deliveries, so it has to be stored as is
+    secret varchar(200) not null,
+    event_types varchar(50)[] not null,
+    created_at timestamp with time zone not null default now()
+);
+
+create index ix_webhooks_user_id on webhooks (user_id);
+
+create table webhook_deliveries (
+    id uuid primary key default gen_random_uuid(),
+    webhook_id uuid not null references webhooks(id) on delete cascade,
+    event_type varchar(50) not null,
+    payload jsonb not null,
+    -- status values:
+    -- 0 - pending
+    -- 1 - delivered
+    -- 2 - failed (no more attempts are made)
+    status smallint not null default 0 check ( status >= 0 and status <= 2 ),
+    attempts integer not null default 0,
+    next_attempt_at timestamp with time zone not null default now(),
+    last_attempt_at timestamp with time zone null,
+    response_status integer null,
+    error varchar(1000) null,
+    created_at timestamp with time zone not null default now()
+);
+
+create index ix_webhook_deliveries_webhook_id on webhook_deliveries (webhook_id, created_at);
+create index ix_webhook_deliveries_pending on webhook_deliveries (next_attempt_at) where status = 0;
@@ -0,0 +1,81 @@
+create table audit_log (
+    -- identity rather than uuid, to keep the order of changes made within one transaction
+    id bigint generated always as identity primary key,
+    user_id uuid not null references users(id) on delete cascade,
+    -- entity types: game, platform, playthrough
+    entity_type varchar(20) not null,
+    entity_id uuid not null,
+    -- actions: create, update, delete
+    action varchar(10) not null,
+    before jsonb null,
+    after jsonb null,
+    changed_at timestamp with time zone not null default now()
+);
+
+create index ix_audit_log_user_id on audit_log (user_id, changed_at);
+create index ix_audit_log_entity on audit_log (entity_id);
+
+-- audit_change records each change of a row in the audit log, within the transaction making the change.
+-- The first trigger argument is the entity type.
+-- Playthroughs have no user of their own, so the user of their game is used; when they are deleted along with the game,
+-- the game is already gone and the user is taken from the record of its deletion, which is why games are audited before deletes.
+create function audit_change()
+    returns trigger
+    as $$
+        declare
+            row_user_id uuid;
+            row_data record;
+            before_data jsonb;
+            after_data jsonb;
+        begin
+            if tg_op = 'UPDATE' and new is not distinct from old then
+                return null;
+            end if;
+
+            if tg_op = 'DELETE' then
+                row_data := old;
+                before_data := to_jsonb(old);
+            elsif tg_op = 'UPDATE' then
+                row_data := new;
+                before_data := to_jsonb(old);
+                after_data := to_jsonb(new);
+            else
+                row_data := new;
+                after_data := to_jsonb(new);
+            end if;
+
+            if tg_argv[0] = 'playthrough' then
+                select g.user_id into row_user_id from games g where g.id = row_data.game_id;
+                if row_user_id is null then
+                    select a.user_id into row_user_id from audit_log a
+                    where a.entity_type = 'game' and a.entity_id = row_data.game_id and a.action = 'delete';
+                end if;
+            else
+                row_user_id := row_data.user_id;
+            end if;
+
+            insert into audit_log (user_id, entity_type, entity_id, action, before, after)
+            values (
+                row_user_id,
+                tg_argv[0],
+                row_data.id,
+                case tg_op when 'INSERT' then 'create' when 'UPDATE' then 'update' else 'delete' end,
+                before_data,
+                after_data
+            );
+
+            return row_data;
+        end;
+    $$
+    language plpgsql;
+
+create trigger tr_platforms_audit after insert or update or delete on platforms
+    for each row execute function audit_change('platform');
+
+create trigger tr_games_audit after insert or update on games
+    for each row execute function audit_change('game');
+create trigger tr_games_audit_delete before delete on games
+    for each row execute function audit_change('game');
+
+create trigger tr_playthroughs_audit after insert or update or delete on playthroughs
+    for each row execute function audit_change('playthrough');
@@ -0,0 +1,82 @@
+alter table platforms add column deleted_at timestamp with time zone null;
+alter table games add column deleted_at timestamp with time zone null;
+alter table playthroughs add column deleted_at timestamp with time zone null;
+
+-- deleted platforms must not block creating new ones with the same names
+alter table platforms drop constraint ix_platform_name;
+alter table platforms drop constraint ix_platform_short_name;
+alter table platforms drop constraint ix_platform_catalog_platform;
+create unique index ix_platform_name on platforms (user_id, name) where deleted_at is null;
+create unique index ix_platform_short_name on platforms (user_id, short_name) where deleted_at is null;
+create unique index ix_platform_catalog_platform on platforms (user_id, catalog_platform_id) where deleted_at is null;
+
+create index ix_platforms_deleted_at on platforms (deleted_at) where deleted_at is not null;
+create index ix_games_deleted_at on games (deleted_at) where deleted_at is not null;
+create index ix_playthroughs_deleted_at on playthroughs (deleted_at) where deleted_at is not null;
+
+-- playthroughs of deleted games are hidden along with the game, even though they are not deleted themselves
+create or replace function check_user_playthrough(user_id uuid, playthrough_id uuid)
+    returns boolean
+    as $$
+        begin
+            return exists(
+                select from playthroughs p
+                join games g on p.game_id = g.id
+                where p.id = check_user_playthrough.playthrough_id and g.user_id = check_user_playthrough.user_id
+                and p.deleted_at is null and g.deleted_at is null
+            );
+        end;
+    $$
+    language plpgsql;
+
+-- soft deletes and restores are recorded as such, while removing rows for good is recorded as a purge if they were deleted before
+create or replace function audit_change()
+    returns trigger
+    as $$
+        declare
+            row_user_id uuid;
+            row_data record;
+            row_action varchar(10);
+            before_data jsonb;
+            after_data jsonb;
+        begin
+            if tg_op = 'UPDATE' and new is not distinct from old then
+                return null;
+            end if;
+
+            if tg_op = 'DELETE' then
+                row_data := old;
+                before_data := to_jsonb(old);
+                row_action := case when old.deleted_at is null then 'delete' else 'purge' end;
+            elsif tg_op = 'UPDATE' then
+                row_data := new;
+                before_data := to_jsonb(old);
+                after_data := to_jsonb(new);
+                row_action := case
+                    when old.deleted_at is null and new.deleted_at is not null then 'delete'
+                    when old.deleted_at is not null and new.deleted_at is null then 'restore'
+                    else 'update' end;
+            else
+                row_data := new;
+                after_data := to_jsonb(new);
+                row_action := 'create';
+            end if;
+
+            if tg_argv[0] = 'playthrough' then
+                select g.user_id into row_user_id from games g where g.id = row_data.game_id;
+                if row_user_id is null then
+                    select a.user_id into row_user_id from audit_log a
+                    where a.entity_type = 'game' and a.entity_id = row_data.game_id and a.action in ('delete', 'purge')
+                    order by a.id desc limit 1;
+                end if;
+            else
+                row_user_id := row_data.user_id;
+            end if;
+
+            insert into audit_log (user_id, entity_type, entity_id, action, before, after)
+            values (row_user_id, tg_argv[0], row_data.id, row_action, before_data, after_data);
+
+            return row_data;
+        end;
+    $$
+    language plpgsql;
@@ -0,0 +1,126 @@
+alter table platforms add column version integer not null default 1;
+alter table games add column version integer not null default 1;
+alter table playthroughs add column version integer not null default 1;
+
+-- bump_version increases the version of each row that is actually changed by an update
+create function bump_version()
+    returns trigger
+    as $$
+        begin
+            if new is distinct from old then
+                new.version := old.version + 1;
+            end if;
+            return new;
+        end;
+    $$
+    language plpgsql;
+
+create trigger tr_platforms_version before update on platforms
+    for each row execute function bump_version();
+create trigger tr_games_version before update on games
+    for each row execute function bump_version();
+create trigger tr_playthroughs_version before update on playthroughs
+    for each row execute function bump_version();
+
+-- tags and play sessions are returned along with games and playthroughs, so changing them creates a new version as well
+create function touch_tagged_game()
+    returns trigger
+    as $$
+        begin
+            if tg_op = 'DELETE' then
+                update games set version = version + 1 where id = old.game_id;
+            else
+                update games set version = version + 1 where id = new.game_id;
+            end if;
+            return null;
+        end;
+    $$
+    language plpgsql;
+
+create trigger tr_games_tags_version after insert or delete on games_tags
+    for each row execute function touch_tagged_game();
+
+create function touch_renamed_tag_games()
+    returns trigger
+    as $$
+        begin
+            update games set version = version + 1 where id in (select game_id from games_tags where tag_id = new.id);
+            return null;
+        end;
+    $$
+    language plpgsql;
+
+create trigger tr_tags_version after update of name on tags
+    for each row when ( old.name is distinct from new.name ) execute function touch_renamed_tag_games();
+
+create function touch_session_playthrough()
+    returns trigger
+    as $$
+        begin
+            if tg_op = 'DELETE' then
+                update playthroughs set version = version + 1 where id = old.playthrough_id;
+            else
+                update playthroughs set version = version + 1 where id = new.playthrough_id;
+                if tg_op = 'UPDATE' and new.playthrough_id <> old.playthrough_id then
+                    update playthroughs set version = version + 1 where id = old.playthrough_id;
+                end if;
+            end if;
+            return null;
+        end;
+    $$
+    language plpgsql;
+
+create trigger tr_play_sessions_version after insert or update or delete on play_sessions
+    for each row execute function touch_session_playthrough();
+
+-- changes of the version alone are not recorded in the audit log
+create or replace function audit_change()
+    returns trigger
+    as $$
+        declare
+            row_user_id uuid;
+            row_data record;
+            row_action varchar(10);
+            before_data jsonb;
+            after_data jsonb;
+        begin
+            if tg_op = 'UPDATE' and to_jsonb(new) - 'version' = to_jsonb(old) - 'version' then
+                return null;
+            end if;
+
+            if tg_op = 'DELETE' then
+                row_data := old;
+                before_data := to_jsonb(old);
+                row_action := case when old.deleted_at is null then 'delete' else 'purge' end;
+            elsif tg_op = 'UPDATE' then
+                row_data := new;
+                before_data := to_jsonb(old);
+                after_data := to_jsonb(new);
+                row_action := case
+                    when old.deleted_at is null and new.deleted_at is not null then 'delete'
+                    when old.deleted_at is not null and new.deleted_at is null then 'restore'
+                    else 'update' end;
+            else
+                row_data := new;
+                after_data := to_jsonb(new);
+                row_action := 'create';
+            end if;
+
+            if tg_argv[0] = 'playthrough' then
+                select g.user_id into row_user_id from games g where g.id = row_data.game_id;
+                if row_user_id is null then
+                    select a.user_id into row_user_id from audit_log a
+                    where a.entity_type = 'game' and a.entity_id = row_data.game_id and a.action in ('delete', 'purge')
+                    order by a.id desc limit 1;
+                end if;
+            else
+                row_user_id := row_data.user_id;
+            end if;
+
+            insert into audit_log (user_id, entity_type, entity_id, action, before, after)
+            values (row_user_id, tg_argv[0], row_data.id, row_action, before_data, after_data);
+
+            return row_data;
+        end;
+    $$
+    language plpgsql;
@@ -0,0 +1,19 @@
+create table user_identities (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    provider_id varchar(256) not null,
+    provider_name varchar(100) not null,
+    email varchar(256) not null,
+    created_at timestamp with time zone not null default now(),
+
+    constraint ix_user_identities_provider_data unique (provider_id, provider_name)
+);
+
+create index ix_user_identities_user_id on user_identities (user_id);
+
+insert into user_identities (user_id, provider_id, provider_name, email)
+select id, provider_id, provider_name, email from users;
+
+alter table users drop constraint ix_users_provider_data;
+alter table users drop column provider_id;
+alter table users drop column provider_name;
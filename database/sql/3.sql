@@ -0,0 +1,15 @@
+create table api_tokens (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    name varchar(100) not null,
+    token_hash char(64) not null constraint ix_api_tokens_token_hash unique,
+    -- scope values:
+    -- 0 - read only
+    -- 1 - read and write
+    scope smallint not null default 0 check ( scope >= 0 and scope <= 1 ),
+    created_at timestamp with time zone not null default now(),
+    last_used_at timestamp with time zone null,
+    expires_at timestamp with time zone null
+);
+
+create index ix_api_tokens_user_id on api_tokens (user_id);
@@ -0,0 +1,4 @@
+alter table platforms drop constraint ix_platform_name;
+alter table platforms drop constraint ix_platform_short_name;
+alter table platforms add constraint ix_platform_name unique (user_id, name);
+alter table platforms add constraint ix_platform_short_name unique (user_id, short_name);
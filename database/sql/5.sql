@@ -0,0 +1,42 @@
+create table catalog_platforms (
+    id uuid primary key default gen_random_uuid(),
+    name varchar(200) not null constraint ix_catalog_platform_name unique,
+    short_name varchar(5) not null constraint ix_catalog_platform_short_name unique,
+    manufacturer varchar(200) null,
+    release_year smallint null
+);
+
+alter table platforms add column catalog_platform_id uuid null references catalog_platforms(id) on delete set null;
+alter table platforms add constraint ix_platform_catalog_platform unique (user_id, catalog_platform_id);
+
+insert into catalog_platforms (name, short_name, manufacturer, release_year) values
+    ('PC', 'PC', null, null),
+    ('Steam Deck', 'DECK', 'Valve', 2022),
+    ('Nintendo Entertainment System', 'NES', 'Nintendo', 1983),
+    ('Super Nintendo Entertainment System', 'SNES', 'Nintendo', 1990),
+    ('Nintendo 64', 'N64', 'Nintendo', 1996),
+    ('Nintendo GameCube', 'GC', 'Nintendo', 2001),
+    ('Wii', 'WII', 'Nintendo', 2006),
+    ('Wii U', 'WIIU', 'Nintendo', 2012),
+    ('Nintendo Switch', 'NS', 'Nintendo', 2017),
+    ('Nintendo Switch 2', 'NS2', 'Nintendo', 2025),
+    ('Game Boy', 'GB', 'Nintendo', 1989),
+    ('Game Boy Color', 'GBC', 'Nintendo', 1998),
+    ('Game Boy Advance', 'GBA', 'Nintendo', 2001),
+    ('Nintendo DS', 'NDS', 'Nintendo', 2004),
+    ('Nintendo 3DS', '3DS', 'Nintendo', 2011),
+    ('PlayStation', 'PS1', 'Sony', 1994),
+    ('PlayStation 2', 'PS2', 'Sony', 2000),
+    ('PlayStation 3', 'PS3', 'Sony', 2006),
+    ('PlayStation 4', 'PS4', 'Sony', 2013),
+    ('PlayStation 5', 'PS5', 'Sony', 2020),
+    ('PlayStation Portable', 'PSP', 'Sony', 2004),
+    ('PlayStation Vita', 'PSV', 'Sony', 2011),
+    ('Xbox', 'XBOX', 'Microsoft', 2001),
+    ('Xbox 360', 'X360', 'Microsoft', 2005),
+    ('Xbox One', 'XB1', 'Microsoft', 2013),
+    ('Xbox Series X|S', 'XSX', 'Microsoft', 2020),
+    ('Sega Master System', 'SMS', 'Sega', 1985),
+    ('Sega Mega Drive', 'MD', 'Sega', 1988),
+    ('Sega Saturn', 'SAT', 'Sega', 1994),
+    ('Sega Dreamcast', 'DC', 'Sega', 1998);
@@ -0,0 +1,16 @@
+create table tags (
+    id uuid primary key default gen_random_uuid(),
+    user_id uuid not null references users(id) on delete cascade,
+    name varchar(100) not null,
+
+    constraint ix_tags_name unique (user_id, name)
+);
+
+create table games_tags (
+    game_id uuid not null references games(id) on delete cascade,
+    tag_id uuid not null references tags(id) on delete cascade,
+
+    constraint pk_games_tags primary key (game_id, tag_id)
+);
+
+create index ix_games_tags_tag_id on games_tags (tag_id);
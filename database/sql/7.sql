@@ -0,0 +1,14 @@
+create table play_sessions (
+    id uuid primary key default gen_random_uuid(),
+    playthrough_id uuid not null references playthroughs(id) on delete cascade,
+    start_time timestamp with time zone not null,
+    -- either the end time or the duration has to be set, the duration is used if both are present
+    end_time timestamp with time zone null,
+    duration_minutes integer null check ( duration_minutes >= 0 ),
+    note varchar(1000) null,
+
+    constraint ck_play_sessions_length check ( end_time is not null or duration_minutes is not null ),
+    constraint ck_play_sessions_end_time check ( end_time is null or end_time >= start_time )
+);
+
+create index ix_play_sessions_playthrough_id on play_sessions (playthrough_id);
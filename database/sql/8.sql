@@ -0,0 +1,6 @@
+-- a user can only have a single timer running at a time
+create table playthrough_timers (
+    user_id uuid primary key references users(id) on delete cascade,
+    playthrough_id uuid not null references playthroughs(id) on delete cascade,
+    started_at timestamp with time zone not null default now()
+);
@@ -0,0 +1,4 @@
+-- the Steam app id is used to recognise games that were already imported from Steam
+alter table games add column steam_app_id integer null check ( steam_app_id > 0 );
+
+create index ix_games_steam_app_id on games (user_id, steam_app_id) where steam_app_id is not null;
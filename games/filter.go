@@ -0,0 +1,65 @@
+package games
+
+import (
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// Condition returns an SQL condition matching the filter, to be appended to the where clause of a query on the games table.
+// Values are appended to args as query parameters, and the extended list is returned along with the condition.
+//
+// The condition refers to the table as games, so it must not be aliased in the query.
+func (f Filter) Condition(args []interface{}) (string, []interface{}) {
+	var condition strings.Builder
+
+	if f.Title != "" {
+		args = append(args, fmt.Sprintf("%%%s%%", f.Title))
+		condition.WriteString(fmt.Sprintf(" and games.title ilike $%d", len(args)))
+	}
+
+	if f.Owned != nil {
+		args = append(args, *f.Owned)
+		condition.WriteString(fmt.Sprintf(" and games.owned = $%d", len(args)))
+	}
+
+	if f.Released != nil {
+		args = append(args, *f.Released)
+		condition.WriteString(fmt.Sprintf(" and games.released = $%d", len(args)))
+	}
+
+	if f.InProgress != nil {
+		not := "not"
+		if *f.InProgress {
+			not = ""
+		}
+		condition.WriteString(fmt.Sprintf(" and %s exists(select * from playthroughs where game_id = games.id and status = 0 and deleted_at is null limit 1)", not))
+	}
+
+	if f.ReleaseFrom != nil {
+		args = append(args, *f.ReleaseFrom)
+		condition.WriteString(fmt.Sprintf(" and %s > $%d", releaseDateEnd, len(args)))
+	}
+
+	if f.ReleaseTo != nil {
+		args = append(args, *f.ReleaseTo)
+		condition.WriteString(fmt.Sprintf(" and games.release_date < $%d", len(args)))
+	}
+
+	if len(f.Tags.AnyOf) > 0 {
+		args = append(args, pq.Array(f.Tags.AnyOf))
+		condition.WriteString(fmt.Sprintf(" and exists(select from games_tags where game_id = games.id and tag_id = any($%d::uuid[]))", len(args)))
+	}
+
+	if len(f.Tags.AllOf) > 0 {
+		args = append(args, pq.Array(f.Tags.AllOf))
+		condition.WriteString(fmt.Sprintf(" and not exists(select from unnest($%d::uuid[]) required(id) where not exists(select from games_tags where game_id = games.id and tag_id = required.id))", len(args)))
+	}
+
+	if len(f.Tags.NoneOf) > 0 {
+		args = append(args, pq.Array(f.Tags.NoneOf))
+		condition.WriteString(fmt.Sprintf(" and not exists(select from games_tags where game_id = games.id and tag_id = any($%d::uuid[]))", len(args)))
+	}
+
+	return condition.String(), args
+}
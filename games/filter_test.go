@@ -0,0 +1,52 @@
+package games
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFilterCondition(t *testing.T) {
+	t.Run("Empty filter", func(t *testing.T) {
+		condition, args := Filter{}.Condition([]interface{}{1})
+
+		assert.Empty(t, condition)
+		assert.Equal(t, []interface{}{1}, args)
+	})
+
+	t.Run("Parameters numbered after existing args", func(t *testing.T) {
+		owned := true
+		inProgress := false
+		filter := Filter{
+			Title:      "zelda",
+			Owned:      &owned,
+			InProgress: &inProgress,
+			Tags:       TagFilter{NoneOf: []uuid.UUID{tests.GetRandomUuid()}},
+		}
+
+		condition, args := filter.Condition([]interface{}{1, 2})
+
+		assert.Len(t, args, 5)
+		assert.Equal(t, "%zelda%", args[2])
+		assert.Equal(t, true, args[3])
+		assert.Contains(t, condition, "games.title ilike $3")
+		assert.Contains(t, condition, "games.owned = $4")
+		assert.Contains(t, condition, "and not exists(select * from playthroughs")
+		assert.Contains(t, condition, "tag_id = any($5::uuid[])")
+		assert.NotContains(t, condition, "released")
+	})
+
+	t.Run("Release range", func(t *testing.T) {
+		from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		condition, args := Filter{ReleaseFrom: &from, ReleaseTo: &to}.Condition([]interface{}{1})
+
+		assert.Equal(t, []interface{}{1, from, to}, args)
+		assert.Contains(t, condition, "interval '3 months'")
+		assert.Contains(t, condition, "end) > $2")
+		assert.Contains(t, condition, "games.release_date < $3")
+	})
+}
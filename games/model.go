@@ -3,7 +3,10 @@ package games
 import (
 	"database/sql"
 	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/tags"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Game struct {
@@ -13,7 +16,73 @@ type Game struct {
 	Title       string
 	Owned       bool
 	ReleaseDate sql.NullTime
-	Released    bool
+	// ReleaseDatePrecision tells which part of the ReleaseDate is known, see NormaliseReleaseDate.
+	ReleaseDatePrecision ReleaseDatePrecision
+	Released             bool
+	// SteamAppId is set for games imported from Steam, and used to recognise them when importing again.
+	SteamAppId sql.NullInt32
+
+	// Tags are only loaded when reading games and are ignored when writing them.
+	Tags []*tags.Tag
+
+	// Version is increased with every change of the game, including changes of its tags.
+	Version int
+}
+
+func (g *Game) webhookData() *webhooks.GameData {
+	data := &webhooks.GameData{
+		Id:                   g.Id,
+		PlatformId:           g.PlatformId,
+		Title:                g.Title,
+		Owned:                g.Owned,
+		ReleaseDatePrecision: int(g.ReleaseDatePrecision),
+		Released:             g.Released,
+	}
+	if g.ReleaseDate.Valid {
+		data.ReleaseDate = &g.ReleaseDate.Time
+	}
+	return data
+}
+
+// Filter limits the games returned to the ones matching all of the set criteria.
+// Empty and nil values are ignored.
+type Filter struct {
+	// Title matches games with titles containing the value, ignoring case.
+	Title      string
+	Owned      *bool
+	Released   *bool
+	InProgress *bool
+	Tags       TagFilter
+	// ReleaseFrom and ReleaseTo match games, that may be released within the half-open range, given the precision of their release dates.
+	// Games without a release date never match.
+	ReleaseFrom *time.Time
+	ReleaseTo   *time.Time
+}
+
+// Sort defines the order of lists of games.
+type Sort int8
+
+const (
+	SortByTitle Sort = iota
+	// SortByReleaseDate orders games by the earliest possible release date, with more precise dates first.
+	// Games without a release date are placed last.
+	SortByReleaseDate
+)
+
+var sortOrder = map[Sort]string{
+	SortByTitle:       "games.title, games.id",
+	SortByReleaseDate: "games.release_date nulls last, games.release_date_precision, games.title, games.id",
+}
+
+// TagFilter limits the games returned to the ones with matching tags.
+// Empty lists are ignored.
+type TagFilter struct {
+	// AnyOf requires the game to have at least one of the tags.
+	AnyOf []uuid.UUID
+	// AllOf requires the game to have every one of the tags.
+	AllOf []uuid.UUID
+	// NoneOf requires the game to have none of the tags.
+	NoneOf []uuid.UUID
 }
 
 func (g *Game) SetId(id uuid.UUID) {
@@ -22,8 +91,12 @@ func (g *Game) SetId(id uuid.UUID) {
 
 func scanGame(row gotabase.Row) (*Game, error) {
 	var game Game
-	if err := row.Scan(&game.Id, &game.PlatformId, &game.Title, &game.Owned, &game.ReleaseDate, &game.Released); err != nil {
+	if err := row.Scan(&game.Id, &game.PlatformId, &game.Title, &game.Owned, &game.ReleaseDate, &game.ReleaseDatePrecision, &game.Released, &game.SteamAppId, &game.Version); err != nil {
 		return nil, err
 	}
 	return &game, nil
 }
+
+func scanIdAndVersion(row gotabase.Row, game *Game) error {
+	return row.Scan(&game.Id, &game.Version)
+}
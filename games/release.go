@@ -0,0 +1,149 @@
+package games
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReleaseDatePrecision tells how much of the release date of a game is known.
+type ReleaseDatePrecision int8
+
+const (
+	ReleaseDateDay ReleaseDatePrecision = iota
+	ReleaseDateMonth
+	ReleaseDateQuarter
+	ReleaseDateYear
+	// ReleaseDateTBA is used for announced games without any release date.
+	ReleaseDateTBA
+)
+
+// releaseDateEnd is an SQL expression returning the end of the period, in which the game is going to be released.
+// It is null for games without a release date.
+const releaseDateEnd = `(games.release_date + case games.release_date_precision
+	when 1 then interval '1 month'
+	when 2 then interval '3 months'
+	when 3 then interval '1 year'
+	else interval '1 day' end)`
+
+// IsValid checks if the precision is one of the known values.
+func (p ReleaseDatePrecision) IsValid() bool {
+	return p >= ReleaseDateDay && p <= ReleaseDateTBA
+}
+
+// Truncate returns the first day of the period of the given precision, which includes the time, at midnight UTC.
+// The period is taken from the date in the time zone of the value, so that it matches the date sent by the client.
+// Times are returned unchanged for the day precision, so that existing dates keep their time of day.
+func (p ReleaseDatePrecision) Truncate(value time.Time) time.Time {
+	switch p {
+	case ReleaseDateMonth:
+		return time.Date(value.Year(), value.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case ReleaseDateQuarter:
+		return time.Date(value.Year(), value.Month()-(value.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	case ReleaseDateYear:
+		return time.Date(value.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return value
+	}
+}
+
+// End returns the end of the period of the given precision, which starts at the value, matching the releaseDateEnd SQL expression.
+func (p ReleaseDatePrecision) End(value time.Time) time.Time {
+	switch p {
+	case ReleaseDateMonth:
+		return value.AddDate(0, 1, 0)
+	case ReleaseDateQuarter:
+		return value.AddDate(0, 3, 0)
+	case ReleaseDateYear:
+		return value.AddDate(1, 0, 0)
+	default:
+		return value.AddDate(0, 0, 1)
+	}
+}
+
+// ReleaseDatePassed checks if the game should have been released by now, according to its release date.
+// Exact dates pass once the day starts, while less precise ones only pass after the whole period ends,
+// as a game announced for a year may be released on any day of it.
+func (g *Game) ReleaseDatePassed(now time.Time) bool {
+	if !g.ReleaseDate.Valid || g.ReleaseDatePrecision == ReleaseDateTBA {
+		return false
+	}
+	if g.ReleaseDatePrecision == ReleaseDateDay {
+		return g.ReleaseDate.Time.Before(now)
+	}
+	return !g.ReleaseDatePrecision.End(g.ReleaseDate.Time).After(now)
+}
+
+// NormaliseReleaseDate makes the release date match its precision.
+// Dates are moved to the start of their period, and removed for games to be announced.
+func (g *Game) NormaliseReleaseDate() {
+	if g.ReleaseDatePrecision == ReleaseDateTBA {
+		g.ReleaseDate = sql.NullTime{}
+		return
+	}
+	if g.ReleaseDate.Valid {
+		g.ReleaseDate.Time = g.ReleaseDatePrecision.Truncate(g.ReleaseDate.Time)
+	}
+}
+
+// FormatReleaseDate formats the release date in UTC according to its precision:
+// as 2006-01-02, 2006-01, 2006-Q1, 2006 or TBA.
+// An empty string is returned for games without a release date.
+func FormatReleaseDate(releaseDate sql.NullTime, precision ReleaseDatePrecision) string {
+	if precision == ReleaseDateTBA {
+		return "TBA"
+	}
+	if !releaseDate.Valid {
+		return ""
+	}
+
+	value := releaseDate.Time.UTC()
+	switch precision {
+	case ReleaseDateMonth:
+		return value.Format("2006-01")
+	case ReleaseDateQuarter:
+		return fmt.Sprintf("%d-Q%d", value.Year(), (value.Month()-1)/3+1)
+	case ReleaseDateYear:
+		return strconv.Itoa(value.Year())
+	default:
+		return value.Format("2006-01-02")
+	}
+}
+
+// ParseReleaseDate reads a release date in any of the formats returned by FormatReleaseDate, ignoring case.
+// Dates are read in UTC, and an empty value results in a game without a release date.
+func ParseReleaseDate(value string) (sql.NullTime, ReleaseDatePrecision, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	switch value {
+	case "":
+		return sql.NullTime{}, ReleaseDateDay, true
+	case "TBA":
+		return sql.NullTime{}, ReleaseDateTBA, true
+	}
+
+	if year, quarter, found := strings.Cut(value, "-Q"); found {
+		yearNumber, yearErr := strconv.Atoi(year)
+		quarterNumber, quarterErr := strconv.Atoi(quarter)
+		if yearErr != nil || quarterErr != nil || len(year) != 4 || quarterNumber < 1 || quarterNumber > 4 {
+			return sql.NullTime{}, 0, false
+		}
+		return sql.NullTime{Valid: true, Time: time.Date(yearNumber, time.Month(quarterNumber*3-2), 1, 0, 0, 0, 0, time.UTC)}, ReleaseDateQuarter, true
+	}
+
+	layouts := []struct {
+		layout    string
+		precision ReleaseDatePrecision
+	}{
+		{"2006-01-02", ReleaseDateDay},
+		{"2006-01", ReleaseDateMonth},
+		{"2006", ReleaseDateYear},
+	}
+	for _, candidate := range layouts {
+		if parsed, err := time.Parse(candidate.layout, value); err == nil {
+			return sql.NullTime{Valid: true, Time: parsed}, candidate.precision, true
+		}
+	}
+	return sql.NullTime{}, 0, false
+}
@@ -0,0 +1,103 @@
+package games
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReleaseDatePrecision(t *testing.T) {
+	value := time.Date(2026, 8, 20, 15, 30, 0, 0, time.FixedZone("test", 2*60*60))
+
+	t.Run("Truncate", func(t *testing.T) {
+		assert.Equal(t, value, ReleaseDateDay.Truncate(value))
+		assert.Equal(t, time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), ReleaseDateMonth.Truncate(value))
+		assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), ReleaseDateQuarter.Truncate(value))
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateYear.Truncate(value))
+	})
+
+	t.Run("Truncate uses the date of the client", func(t *testing.T) {
+		midnight := time.Date(2026, 7, 1, 0, 0, 0, 0, time.FixedZone("test", 2*60*60))
+
+		assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), ReleaseDateQuarter.Truncate(midnight))
+	})
+
+	t.Run("Normalise TBA", func(t *testing.T) {
+		game := Game{ReleaseDate: sql.NullTime{Valid: true, Time: value}, ReleaseDatePrecision: ReleaseDateTBA}
+
+		game.NormaliseReleaseDate()
+
+		assert.False(t, game.ReleaseDate.Valid)
+	})
+
+	t.Run("Release date passed", func(t *testing.T) {
+		now := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+		cases := []struct {
+			date      time.Time
+			precision ReleaseDatePrecision
+			expected  bool
+		}{
+			{time.Date(2026, 9, 14, 0, 0, 0, 0, time.UTC), ReleaseDateDay, true},
+			{time.Date(2026, 9, 16, 0, 0, 0, 0, time.UTC), ReleaseDateDay, false},
+			{time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), ReleaseDateMonth, true},
+			{time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), ReleaseDateMonth, false},
+			{time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), ReleaseDateQuarter, false},
+			{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateYear, true},
+			{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateYear, false},
+		}
+		for _, c := range cases {
+			game := Game{ReleaseDate: sql.NullTime{Valid: true, Time: c.date}, ReleaseDatePrecision: c.precision}
+
+			assert.Equal(t, c.expected, game.ReleaseDatePassed(now), "%v %v", c.date, c.precision)
+		}
+		assert.False(t, (&Game{ReleaseDatePrecision: ReleaseDateTBA}).ReleaseDatePassed(now))
+	})
+}
+
+func TestFormatAndParseReleaseDate(t *testing.T) {
+	cases := []struct {
+		text      string
+		date      time.Time
+		precision ReleaseDatePrecision
+	}{
+		{"2026-08-20", time.Date(2026, 8, 20, 0, 0, 0, 0, time.UTC), ReleaseDateDay},
+		{"2026-08", time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), ReleaseDateMonth},
+		{"2026-Q3", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), ReleaseDateQuarter},
+		{"2026", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateYear},
+		{"TBA", time.Time{}, ReleaseDateTBA},
+	}
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			date, precision, ok := ParseReleaseDate(c.text)
+
+			assert.True(t, ok)
+			assert.Equal(t, c.precision, precision)
+			assert.Equal(t, c.date, date.Time)
+			assert.Equal(t, c.text, FormatReleaseDate(date, precision))
+		})
+	}
+
+	t.Run("Lower case accepted", func(t *testing.T) {
+		_, precision, ok := ParseReleaseDate("2026-q1")
+
+		assert.True(t, ok)
+		assert.Equal(t, ReleaseDateQuarter, precision)
+	})
+
+	t.Run("Empty value", func(t *testing.T) {
+		date, _, ok := ParseReleaseDate("")
+
+		assert.True(t, ok)
+		assert.False(t, date.Valid)
+		assert.Empty(t, FormatReleaseDate(date, ReleaseDateDay))
+	})
+
+	t.Run("Invalid values", func(t *testing.T) {
+		for _, value := range []string{"2026-Q5", "2026-Q0", "26-Q1", "2026/01", "soon"} {
+			_, _, ok := ParseReleaseDate(value)
+
+			assert.False(t, ok, value)
+		}
+	})
+}
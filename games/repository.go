@@ -1,71 +1,139 @@
 package games
 
 import (
-	"fmt"
 	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/tags"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"time"
 )
 
-// GetGames returns a list of games with offset and limit used for pagination.
-func GetGames(offset int, limit int, userId uuid.UUID, title string, owned *bool, released *bool, inProgress *bool) ([]*Game, error) {
-	query := `select id, platform_id, title, owned, release_date, released from games where user_id = $3 %s order by title offset $1 limit $2`
-	args := []interface{}{offset, limit, userId}
-
-	if title != "" {
-		args = append(args, fmt.Sprintf("%%%s%%", title))
-		query = fmt.Sprintf(query, fmt.Sprintf("and title ilike $%d %%s", len(args)))
+// GetGames returns a list of games matching the filter, with offset and limit used for pagination.
+func GetGames(offset int, limit int, userId uuid.UUID, filter Filter, sort Sort) ([]*Game, error) {
+	order, ok := sortOrder[sort]
+	if !ok {
+		order = sortOrder[SortByTitle]
 	}
+	condition, args := filter.Condition([]interface{}{offset, limit, userId})
+	query := `select id, platform_id, title, owned, release_date, release_date_precision, released, steam_app_id, version from games where user_id = $3 and deleted_at is null ` + condition + ` order by ` + order + ` offset $1 limit $2`
 
-	if owned != nil {
-		args = append(args, *owned)
-		query = fmt.Sprintf(query, fmt.Sprintf("and owned = $%d %%s ", len(args)))
+	list, err := operations.QueryRows(getDatabase(), scanGame, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	return list, loadTags(list, userId)
+}
 
-	if released != nil {
-		args = append(args, *released)
-		query = fmt.Sprintf(query, fmt.Sprintf("and released = $%d %%s ", len(args)))
+// GetAllGames returns a complete list of games of the user, without pagination.
+func GetAllGames(userId uuid.UUID) ([]*Game, error) {
+	query := `select id, platform_id, title, owned, release_date, release_date_precision, released, steam_app_id, version from games where user_id = $1 and deleted_at is null order by title`
+	list, err := operations.QueryRows(getDatabase(), scanGame, query, userId)
+	if err != nil {
+		return nil, err
 	}
+	return list, loadTags(list, userId)
+}
 
-	if inProgress != nil {
-		not := "not"
-		if *inProgress {
-			not = ""
-		}
-		query = fmt.Sprintf(query, fmt.Sprintf("and %s exists(select * from playthroughs where game_id = games.id and status = 0 limit 1) %%s", not))
+// GetUpcomingGames returns all games of the user, which are not released yet, ordered by their earliest possible release date.
+// Games without a release date are placed last.
+func GetUpcomingGames(userId uuid.UUID) ([]*Game, error) {
+	query := `select id, platform_id, title, owned, release_date, release_date_precision, released, steam_app_id, version from games where user_id = $1 and deleted_at is null and not released order by ` + sortOrder[SortByReleaseDate]
+	list, err := operations.QueryRows(getDatabase(), scanGame, query, userId)
+	if err != nil {
+		return nil, err
 	}
-
-	query = fmt.Sprintf(query, "")
-	return operations.QueryRows(getDatabase(), scanGame, query, args...)
+	return list, loadTags(list, userId)
 }
 
 // GetGame returns a single game selected by id.
 func GetGame(id uuid.UUID, userId uuid.UUID) (*Game, error) {
-	query := `select id, platform_id, title, owned, release_date, released from games where id = $1 and user_id = $2`
-	return operations.QueryRow(getDatabase(), scanGame, query, id, userId)
+	query := `select id, platform_id, title, owned, release_date, release_date_precision, released, steam_app_id, version from games where id = $1 and user_id = $2 and deleted_at is null`
+	game, err := operations.QueryRow(getDatabase(), scanGame, query, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return game, loadTags([]*Game{game}, userId)
 }
 
 // CreateGame creates a new game.
 func CreateGame(game *Game, userId uuid.UUID) error {
-	query := `insert into games (title, platform_id, owned, release_date, released, user_id) values ($1, $2, $3, $4, $5, $6) returning id`
-	return operations.CreateRowWithId(getDatabase(), game, query, game.Title, game.PlatformId, game.Owned, game.ReleaseDate, game.Released, userId)
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		return CreateGameTx(tx, game, userId)
+	})
+}
+
+// CreateGameTx works like CreateGame, but uses the provided connector, so that it can be run in a transaction.
+// The release date is normalised to match its precision.
+// The game.created event is queued for the webhooks of the user.
+func CreateGameTx(connector gotabase.Connector, game *Game, userId uuid.UUID) error {
+	game.NormaliseReleaseDate()
+	query := `insert into games (title, platform_id, owned, release_date, release_date_precision, released, steam_app_id, user_id) values ($1, $2, $3, $4, $5, $6, $7, $8) returning id, version`
+	if err := operations.CreateRowWithScan(connector, game, scanIdAndVersion, query, game.Title, game.PlatformId, game.Owned, game.ReleaseDate, game.ReleaseDatePrecision, game.Released, game.SteamAppId, userId); err != nil {
+		return err
+	}
+	return webhooks.EmitTx(connector, webhooks.EventGameCreated, game.webhookData(), userId)
 }
 
-// UpdateGame updates details about a single game in the database.
+// UpdateGame updates details about a single game in the database, and sets its new version.
+// The release date is normalised to match its precision.
+// The Steam app id is not changed, use SetSteamAppIdTx instead.
+//
+// If the version of the game is set, the game is only updated if it still has that version, VersionMismatchErr from utils is returned otherwise.
 func UpdateGame(game *Game, userId uuid.UUID) error {
-	query := `update games set title = $2, platform_id = $3, owned = $4, release_date = $5, released = $6 where id = $1 and user_id = $7`
-	return operations.UpdateRow(getDatabase(), query, game.Id, game.Title, game.PlatformId, game.Owned, game.ReleaseDate, game.Released, userId)
+	game.NormaliseReleaseDate()
+	query := `update games set title = $2, platform_id = $3, owned = $4, release_date = $5, release_date_precision = $6, released = $7
+		where id = $1 and user_id = $8 and deleted_at is null and ($9::integer = 0 or version = $9)
+		returning version`
+	version, err := utils.UpdateVersionedRow(getDatabase(), query, game.Id, game.Title, game.PlatformId, game.Owned, game.ReleaseDate, game.ReleaseDatePrecision, game.Released, userId, game.Version)
+	if err != nil {
+		return utils.CheckVersionMismatch(err, game.Version, func() bool { return IsUserAuthorised(getDatabase(), game.Id, userId) })
+	}
+	game.Version = version
+	return nil
 }
 
-// DeleteGame deletes a single game from the database
-func DeleteGame(id uuid.UUID, userId uuid.UUID) error {
-	query := `delete from games where id = $1 and user_id = $2`
-	return operations.DeleteRow(getDatabase(), query, id, userId)
+// SetSteamAppIdTx links the game to a Steam app.
+func SetSteamAppIdTx(connector gotabase.Connector, id uuid.UUID, steamAppId int32, userId uuid.UUID) error {
+	query := `update games set steam_app_id = $2 where id = $1 and user_id = $3 and deleted_at is null`
+	return operations.UpdateRow(connector, query, id, steamAppId, userId)
+}
+
+// MarkReleasedTx marks games of all users as released, once their release date has passed as of now, returning the number of games changed.
+// Dates less precise than a day only pass after their whole period ends, the same way as in [Game.ReleaseDatePassed].
+func MarkReleasedTx(connector gotabase.Connector, now time.Time) (int, error) {
+	query := `update games set released = true
+		where not released and deleted_at is null and release_date is not null and release_date_precision <> 4
+		and (case when release_date_precision = 0 then release_date < $1 else ` + releaseDateEnd + ` <= $1 end)`
+	result, err := connector.Exec(query, now)
+	if err != nil {
+		return 0, operations.Errors.HandleError(err)
+	}
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// DeleteGame moves a single game to the trash, hiding it along with its playthroughs and discarding their timers.
+// The game is removed for good once the trash is purged.
+//
+// If the version is not 0, the game is only deleted if it still has that version, VersionMismatchErr from utils is returned otherwise.
+func DeleteGame(id uuid.UUID, version int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		query := `update games set deleted_at = now() where id = $1 and user_id = $2 and deleted_at is null and ($3::integer = 0 or version = $3)`
+		if err := operations.UpdateRow(tx, query, id, userId, version); err != nil {
+			return utils.CheckVersionMismatch(err, version, func() bool { return IsUserAuthorised(tx, id, userId) })
+		}
+
+		query = `delete from playthrough_timers t using playthroughs p where p.id = t.playthrough_id and p.game_id = $1`
+		_, err := tx.Exec(query, id)
+		return operations.Errors.HandleError(err)
+	})
 }
 
 func IsUserAuthorised(connector gotabase.Connector, gameId uuid.UUID, userId uuid.UUID) bool {
-	query := `select count(1) from games where id = $1 and user_id = $2`
+	query := `select count(1) from games where id = $1 and user_id = $2 and deleted_at is null`
 	row, err := connector.QueryRow(query, gameId, userId)
 	if err != nil {
 		log.Warnf("Failed to check user authorised: %v", err)
@@ -79,3 +147,20 @@ func IsUserAuthorised(connector gotabase.Connector, gameId uuid.UUID, userId uui
 	}
 	return count > 0
 }
+
+func loadTags(list []*Game, userId uuid.UUID) error {
+	ids := make([]uuid.UUID, len(list))
+	for i, game := range list {
+		ids[i] = game.Id
+	}
+
+	gameTags, err := tags.GetTagsForGames(ids, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range list {
+		game.Tags = gameTags[game.Id]
+	}
+	return nil
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func makePlatform(userId uuid.UUID) uuid.UUID {
@@ -26,6 +29,17 @@ func makePlaythrough(gameId uuid.UUID, status int) uuid.UUID {
 	return id
 }
 
+func makeTag(name string, userId uuid.UUID, gameIds ...uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	_, err := getDatabase().Exec(`insert into tags (id, name, user_id) values ($1, $2, $3)`, id, name, userId)
+	tests.PanicOnErr(err)
+	for _, gameId := range gameIds {
+		_, err = getDatabase().Exec(`insert into games_tags (game_id, tag_id) values ($1, $2)`, gameId, id)
+		tests.PanicOnErr(err)
+	}
+	return id
+}
+
 func makeDefaultTestGame(platformId uuid.UUID) Game {
 	return Game{
 		PlatformId:  platformId,
@@ -45,7 +59,7 @@ func TestCreateGame(t *testing.T) {
 		err := CreateGame(&game, userId)
 
 		assert.NoError(t, err)
-		dbRow, err := getDatabase().QueryRow("select id, platform_id, title, owned, release_date, released from games where id = $1", game.Id)
+		dbRow, err := getDatabase().QueryRow("select id, platform_id, title, owned, release_date, release_date_precision, released, steam_app_id, version from games where id = $1", game.Id)
 		tests.PanicOnErr(err)
 		dbGame, err := scanGame(dbRow)
 		dbGame.ReleaseDate.Time = dbGame.ReleaseDate.Time.UTC()
@@ -62,6 +76,21 @@ func TestCreateGame(t *testing.T) {
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
+
+	t.Run("Webhook event queued", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := &webhooks.Webhook{Url: "http://localhost/hook", Secret: "test-secret-0123456789", Events: []webhooks.EventType{webhooks.EventGameCreated}}
+		tests.PanicOnErr(webhooks.CreateWebhook(webhook, userId))
+		game := makeDefaultTestGame(makePlatform(userId))
+
+		tests.PanicOnErr(CreateGame(&game, userId))
+
+		deliveries, err := webhooks.GetDeliveries(webhook.Id, 0, 10, userId)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Contains(t, string(deliveries[0].Payload), game.Id.String())
+	})
 }
 
 func TestGetGames(t *testing.T) {
@@ -100,7 +129,7 @@ func TestGetGames(t *testing.T) {
 	tests.PanicOnErr(CreateGame(&clone, tests.MakeTestUserId(getDatabase())))
 
 	t.Run("Get all games", func(t *testing.T) {
-		list, err := GetGames(0, 100, userId, "game", nil, nil, nil)
+		list, err := GetGames(0, 100, userId, Filter{Title: "game"}, SortByTitle)
 
 		assert.NoError(t, err)
 		assert.Len(t, list, len(games))
@@ -115,7 +144,7 @@ func TestGetGames(t *testing.T) {
 	t.Run("Get only owned", func(t *testing.T) {
 		for _, arg := range bools {
 			t.Run(fmt.Sprint(arg), func(t *testing.T) {
-				list, err := GetGames(0, 100, userId, "", tests.GetPointerFromValue(arg), nil, nil)
+				list, err := GetGames(0, 100, userId, Filter{Owned: tests.GetPointerFromValue(arg)}, SortByTitle)
 
 				assert.NoError(t, err)
 				assert.NotEmpty(t, list)
@@ -129,7 +158,7 @@ func TestGetGames(t *testing.T) {
 	t.Run("Get only released", func(t *testing.T) {
 		for _, arg := range bools {
 			t.Run(fmt.Sprint(arg), func(t *testing.T) {
-				list, err := GetGames(0, 100, userId, "", nil, tests.GetPointerFromValue(arg), nil)
+				list, err := GetGames(0, 100, userId, Filter{Released: tests.GetPointerFromValue(arg)}, SortByTitle)
 
 				assert.NoError(t, err)
 				assert.NotEmpty(t, list)
@@ -141,7 +170,7 @@ func TestGetGames(t *testing.T) {
 	})
 
 	t.Run("Only in progress", func(t *testing.T) {
-		list, err := GetGames(0, 100, userId, "", nil, nil, tests.GetPointerFromValue(true))
+		list, err := GetGames(0, 100, userId, Filter{InProgress: tests.GetPointerFromValue(true)}, SortByTitle)
 
 		assert.NoError(t, err)
 		assert.Len(t, list, 1)
@@ -149,7 +178,7 @@ func TestGetGames(t *testing.T) {
 	})
 
 	t.Run("Only not in progress", func(t *testing.T) {
-		list, err := GetGames(0, 100, userId, "", nil, nil, tests.GetPointerFromValue(false))
+		list, err := GetGames(0, 100, userId, Filter{InProgress: tests.GetPointerFromValue(false)}, SortByTitle)
 
 		assert.NoError(t, err)
 		assert.Len(t, list, 2)
@@ -159,7 +188,7 @@ func TestGetGames(t *testing.T) {
 	})
 
 	t.Run("Filter by title", func(t *testing.T) {
-		list, err := GetGames(0, 100, userId, "1", nil, nil, nil)
+		list, err := GetGames(0, 100, userId, Filter{Title: "1"}, SortByTitle)
 
 		assert.NoError(t, err)
 		assert.Len(t, list, 1)
@@ -167,6 +196,132 @@ func TestGetGames(t *testing.T) {
 	})
 }
 
+func TestGetGamesTags(t *testing.T) {
+	tests.GetDatabaseWithCleanup(t)
+	userId := tests.MakeTestUserId(getDatabase())
+	platformId := makePlatform(userId)
+	games := make([]Game, 3)
+	for i := range games {
+		games[i] = makeDefaultTestGame(platformId)
+		tests.PanicOnErr(CreateGame(&games[i], userId))
+	}
+	coop := makeTag("co-op", userId, games[0].Id, games[1].Id)
+	roguelike := makeTag("roguelike", userId, games[1].Id)
+
+	getIds := func(list []*Game) []uuid.UUID {
+		ids := make([]uuid.UUID, len(list))
+		for i, game := range list {
+			ids[i] = game.Id
+		}
+		return ids
+	}
+
+	t.Run("Tags loaded", func(t *testing.T) {
+		game, err := GetGame(games[1].Id, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, game.Tags, 2)
+		assert.Equal(t, "co-op", game.Tags[0].Name)
+		assert.Equal(t, "roguelike", game.Tags[1].Name)
+	})
+
+	t.Run("Any of", func(t *testing.T) {
+		list, err := GetGames(0, 100, userId, Filter{Tags: TagFilter{AnyOf: []uuid.UUID{coop, roguelike}}}, SortByTitle)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{games[0].Id, games[1].Id}, getIds(list))
+	})
+
+	t.Run("All of", func(t *testing.T) {
+		list, err := GetGames(0, 100, userId, Filter{Tags: TagFilter{AllOf: []uuid.UUID{coop, roguelike}}}, SortByTitle)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{games[1].Id}, getIds(list))
+	})
+
+	t.Run("None of", func(t *testing.T) {
+		list, err := GetGames(0, 100, userId, Filter{Tags: TagFilter{NoneOf: []uuid.UUID{roguelike}}}, SortByTitle)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{games[0].Id, games[2].Id}, getIds(list))
+	})
+}
+
+func TestGetAllGames(t *testing.T) {
+	t.Run("All games of the user returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform(userId)
+		for i := 0; i < 3; i++ {
+			game := makeDefaultTestGame(platformId)
+			tests.PanicOnErr(CreateGame(&game, userId))
+		}
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		other := makeDefaultTestGame(makePlatform(otherUserId))
+		tests.PanicOnErr(CreateGame(&other, otherUserId))
+
+		list, err := GetAllGames(userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 3)
+	})
+}
+
+func TestGetGamesByReleaseDate(t *testing.T) {
+	tests.GetDatabaseWithCleanup(t)
+	userId := tests.MakeTestUserId(getDatabase())
+	platformId := makePlatform(userId)
+	makeGame := func(title string, releaseDate time.Time, precision ReleaseDatePrecision) Game {
+		game := Game{PlatformId: platformId, Title: title, ReleaseDate: sql.NullTime{Valid: !releaseDate.IsZero(), Time: releaseDate}, ReleaseDatePrecision: precision}
+		tests.PanicOnErr(CreateGame(&game, userId))
+		return game
+	}
+	tba := makeGame("a tba", time.Time{}, ReleaseDateTBA)
+	year := makeGame("b year", time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC), ReleaseDateYear)
+	quarter := makeGame("c quarter", time.Date(2026, 8, 20, 0, 0, 0, 0, time.UTC), ReleaseDateQuarter)
+	day := makeGame("d day", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateDay)
+	released := makeGame("e released", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), ReleaseDateDay)
+	tests.PanicOnErr(UpdateGame(&Game{Id: released.Id, PlatformId: platformId, Title: released.Title, ReleaseDate: released.ReleaseDate, Released: true}, userId))
+	titles := func(list []*Game) []string {
+		result := make([]string, len(list))
+		for i, game := range list {
+			result[i] = game.Title
+		}
+		return result
+	}
+
+	t.Run("Dates normalised", func(t *testing.T) {
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), year.ReleaseDate.Time)
+		assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), quarter.ReleaseDate.Time)
+		assert.False(t, tba.ReleaseDate.Valid)
+	})
+
+	t.Run("Sorted by earliest release date", func(t *testing.T) {
+		list, err := GetGames(0, 100, userId, Filter{}, SortByReleaseDate)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{released.Title, day.Title, year.Title, quarter.Title, tba.Title}, titles(list))
+	})
+
+	t.Run("Filtered by possible release period", func(t *testing.T) {
+		from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+		list, err := GetGames(0, 100, userId, Filter{ReleaseFrom: &from, ReleaseTo: &to}, SortByReleaseDate)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{year.Title, quarter.Title}, titles(list))
+	})
+
+	t.Run("Upcoming games", func(t *testing.T) {
+		list, err := GetUpcomingGames(userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{day.Title, year.Title, quarter.Title, tba.Title}, titles(list))
+		assert.Equal(t, ReleaseDateYear, list[1].ReleaseDatePrecision)
+	})
+}
+
 func TestGetGame(t *testing.T) {
 	t.Run("Game exists - returned", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
@@ -245,6 +400,50 @@ func TestUpdateGame(t *testing.T) {
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
+
+	t.Run("Version matches - version increased", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		game := makeDefaultTestGame(makePlatform(userId))
+		tests.PanicOnErr(CreateGame(&game, userId))
+		game.Title = "updated game"
+
+		err := UpdateGame(&game, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, game.Version)
+	})
+
+	t.Run("Version changed in the meantime - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		game := makeDefaultTestGame(makePlatform(userId))
+		tests.PanicOnErr(CreateGame(&game, userId))
+		other := game
+		other.Title = "other device"
+		tests.PanicOnErr(UpdateGame(&other, userId))
+		game.Title = "updated game"
+
+		err := UpdateGame(&game, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+		dbGame, err := GetGame(game.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, "other device", dbGame.Title)
+	})
+
+	t.Run("Tag attached - version increased", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		game := makeDefaultTestGame(makePlatform(userId))
+		tests.PanicOnErr(CreateGame(&game, userId))
+
+		makeTag("co-op", userId, game.Id)
+
+		dbGame, err := GetGame(game.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, 2, dbGame.Version)
+	})
 }
 
 func TestDeleteGame(t *testing.T) {
@@ -254,7 +453,7 @@ func TestDeleteGame(t *testing.T) {
 		game := makeDefaultTestGame(makePlatform(userId))
 		tests.PanicOnErr(CreateGame(&game, userId))
 
-		err := DeleteGame(game.Id, userId)
+		err := DeleteGame(game.Id, 0, userId)
 
 		assert.NoError(t, err)
 		_, err = GetGame(game.Id, userId)
@@ -265,7 +464,7 @@ func TestDeleteGame(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
 		userId := tests.MakeTestUserId(getDatabase())
 
-		err := DeleteGame(tests.GetRandomUuid(), userId)
+		err := DeleteGame(tests.GetRandomUuid(), 0, userId)
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -276,8 +475,24 @@ func TestDeleteGame(t *testing.T) {
 		game := makeDefaultTestGame(makePlatform(userId))
 		tests.PanicOnErr(CreateGame(&game, userId))
 
-		err := DeleteGame(tests.GetRandomUuid(), tests.MakeTestUserId(getDatabase()))
+		err := DeleteGame(tests.GetRandomUuid(), 0, tests.MakeTestUserId(getDatabase()))
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
+
+	t.Run("Version changed in the meantime - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		game := makeDefaultTestGame(makePlatform(userId))
+		tests.PanicOnErr(CreateGame(&game, userId))
+		version := game.Version
+		game.Title = "updated game"
+		tests.PanicOnErr(UpdateGame(&game, userId))
+
+		err := DeleteGame(game.Id, version, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+		_, err = GetGame(game.Id, userId)
+		assert.NoError(t, err)
+	})
 }
@@ -0,0 +1,8 @@
+package games
+
+import "strings"
+
+// NormaliseTitle returns the title in the form used to match games by title when importing them, ignoring case and surrounding whitespace.
+func NormaliseTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
@@ -0,0 +1,11 @@
+package games
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormaliseTitle(t *testing.T) {
+	assert.Equal(t, "hollow knight", NormaliseTitle("  Hollow Knight\t"))
+	assert.Equal(t, NormaliseTitle("CELESTE"), NormaliseTitle("celeste"))
+}
@@ -23,8 +23,8 @@ func MakeTestUserId(connector gotabase.Connector) uuid.UUID {
 }
 
 func MakeTestUser(connector gotabase.Connector, id uuid.UUID) {
-	query := `insert into users (id, provider_id, provider_name, email) values ($1, $2, $3, $4)`
-	_, err := connector.Exec(query, id, id.String(), id.String(), id.String())
+	query := `insert into users (id, email) values ($1, $2)`
+	_, err := connector.Exec(query, id, id.String())
 	PanicOnErr(err)
 }
 
@@ -0,0 +1,8 @@
+package jobs
+
+import "errors"
+
+var (
+	// notDueErr is used to roll back the transaction, when the job does not have to be run.
+	notDueErr = errors.New("job is not due")
+)
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"hash/fnv"
+	"time"
+)
+
+// Job is a maintenance task, run periodically by the [Scheduler].
+type Job struct {
+	// Name identifies the job, and must be unique.
+	Name     string
+	Interval time.Duration
+	// Run performs the task in the transaction holding the lock of the job, returning a summary of the changes made.
+	// The time of the run is provided by the clock of the [Scheduler].
+	Run func(tx gotabase.Connector, now time.Time) (string, error)
+}
+
+// Run is the last recorded run of a [Job].
+type Run struct {
+	Name       string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Succeeded  bool
+	Message    string
+}
+
+// lockKey returns the key of the Postgres advisory lock of the job.
+func (j *Job) lockKey() int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("ludivault.jobs." + j.Name))
+	return int64(hash.Sum64())
+}
+
+func scanRun(row gotabase.Row) (*Run, error) {
+	var run Run
+	if err := row.Scan(&run.Name, &run.StartedAt, &run.FinishedAt, &run.Succeeded, &run.Message); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"time"
+)
+
+// MarkReleasedJob marks games of all users as released, once their release date has passed.
+var MarkReleasedJob = &Job{
+	Name:     "mark-released",
+	Interval: time.Hour,
+	Run: func(tx gotabase.Connector, now time.Time) (string, error) {
+		count, err := games.MarkReleasedTx(tx, now)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d games marked as released", count), nil
+	},
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"time"
+)
+
+// GetRuns returns the last recorded run of each job.
+func GetRuns() ([]*Run, error) {
+	query := `select name, started_at, finished_at, succeeded, message from job_runs order by name`
+	return operations.QueryRows(getDatabase(), scanRun, query)
+}
+
+// GetRun returns the last recorded run of a single job.
+func GetRun(name string) (*Run, error) {
+	query := `select name, started_at, finished_at, succeeded, message from job_runs where name = $1`
+	return operations.QueryRow(getDatabase(), scanRun, query, name)
+}
+
+// tryLock takes the advisory lock of the job, which is held until the end of the transaction.
+// False is returned if the lock is already held by another transaction, possibly of another instance of the application.
+func tryLock(tx gotabase.Connector, job *Job) (bool, error) {
+	row, err := tx.QueryRow(`select pg_try_advisory_xact_lock($1)`, job.lockKey())
+	if err != nil {
+		return false, operations.Errors.HandleError(err)
+	}
+	var locked bool
+	if err = row.Scan(&locked); err != nil {
+		return false, operations.Errors.HandleError(err)
+	}
+	return locked, nil
+}
+
+// isDue checks if the job was not started within its interval before now.
+func isDue(connector gotabase.Connector, job *Job, now time.Time) (bool, error) {
+	row, err := connector.QueryRow(`select count(1) from job_runs where name = $1 and started_at > $2`, job.Name, now.Add(-job.Interval))
+	if err != nil {
+		return false, operations.Errors.HandleError(err)
+	}
+	var count int
+	if err = row.Scan(&count); err != nil {
+		return false, operations.Errors.HandleError(err)
+	}
+	return count == 0, nil
+}
+
+func recordRun(connector gotabase.Connector, run *Run) error {
+	query := `insert into job_runs (name, started_at, finished_at, succeeded, message) values ($1, $2, $3, $4, $5)
+		on conflict (name) do update set started_at = excluded.started_at, finished_at = excluded.finished_at, succeeded = excluded.succeeded, message = excluded.message`
+	_, err := connector.Exec(query, run.Name, run.StartedAt, run.FinishedAt, run.Succeeded, run.Message)
+	return operations.Errors.HandleError(err)
+}
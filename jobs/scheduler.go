@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// checkInterval is how often the scheduler checks which jobs are due.
+const checkInterval = time.Minute
+
+// Scheduler runs jobs periodically.
+//
+// Jobs are safe to run on several instances of the application sharing the database:
+// each run holds a Postgres advisory lock of the job, and the job is only run if no instance started it within its interval.
+type Scheduler struct {
+	jobs []*Job
+	// clock returns the current time, and can be replaced in tests.
+	clock func() time.Time
+}
+
+// NewScheduler creates a [Scheduler] of the jobs, using the clock to tell the current time.
+func NewScheduler(clock func() time.Time, jobs ...*Job) *Scheduler {
+	return &Scheduler{jobs: jobs, clock: clock}
+}
+
+// Start runs the jobs in the background, checking which ones are due right away and then every minute, until the context is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for {
+			s.RunDue()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunDue runs all jobs, which are due according to the clock, one after another.
+// Jobs being run by another instance at the same time are skipped.
+// Outcomes of the runs are recorded in the database, failures are logged as well.
+func (s *Scheduler) RunDue() {
+	for _, job := range s.jobs {
+		if err := s.run(job); err != nil {
+			log.Warnf("Job %s failed: %v", job.Name, err)
+		}
+	}
+}
+
+func (s *Scheduler) run(job *Job) error {
+	run := &Run{Name: job.Name, StartedAt: s.clock()}
+	err := utils.RunInTransaction(func(tx gotabase.Connector) error {
+		locked, err := tryLock(tx, job)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			return notDueErr
+		}
+		due, err := isDue(tx, job, run.StartedAt)
+		if err != nil {
+			return err
+		}
+		if !due {
+			return notDueErr
+		}
+
+		if run.Message, err = job.Run(tx, run.StartedAt); err != nil {
+			return err
+		}
+		run.Succeeded = true
+		run.FinishedAt = s.clock()
+		return recordRun(tx, run)
+	})
+	if errors.Is(err, notDueErr) {
+		return nil
+	}
+	if err != nil {
+		// the transaction was rolled back, so the failure is recorded separately
+		run.Succeeded = false
+		run.FinishedAt = s.clock()
+		run.Message = err.Error()
+		if recordErr := recordRun(getDatabase(), run); recordErr != nil {
+			log.Warnf("Failed to record run of job %s: %v", job.Name, recordErr)
+		}
+		return err
+	}
+
+	log.Debugf("Job %s finished: %s", job.Name, run.Message)
+	return nil
+}
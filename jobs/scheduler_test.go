@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// testClock is a clock, which only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func makeCountingJob(name string, err error) (*Job, *int) {
+	runs := 0
+	return &Job{
+		Name:     name,
+		Interval: time.Hour,
+		Run: func(tx gotabase.Connector, now time.Time) (string, error) {
+			runs++
+			return "done", err
+		},
+	}, &runs
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("Run recorded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		job, runs := makeCountingJob("test", nil)
+
+		NewScheduler(clock.Now, job).RunDue()
+
+		assert.Equal(t, 1, *runs)
+		run, err := GetRun("test")
+		assert.NoError(t, err)
+		assert.True(t, run.Succeeded)
+		assert.Equal(t, "done", run.Message)
+		assert.True(t, clock.now.Equal(run.StartedAt))
+	})
+
+	t.Run("Run again only after interval", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		job, runs := makeCountingJob("test", nil)
+		scheduler := NewScheduler(clock.Now, job)
+		scheduler.RunDue()
+
+		clock.now = clock.now.Add(30 * time.Minute)
+		scheduler.RunDue()
+		assert.Equal(t, 1, *runs)
+
+		clock.now = clock.now.Add(30 * time.Minute)
+		scheduler.RunDue()
+		assert.Equal(t, 2, *runs)
+	})
+
+	t.Run("Run shared between instances", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		job, runs := makeCountingJob("test", nil)
+
+		NewScheduler(clock.Now, job).RunDue()
+		NewScheduler(clock.Now, job).RunDue()
+
+		assert.Equal(t, 1, *runs)
+	})
+
+	t.Run("Locked job skipped", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		job, runs := makeCountingJob("test", nil)
+		tx, err := gotabase.BeginTransaction()
+		tests.PanicOnErr(err)
+		defer tx.Rollback()
+		locked, err := tryLock(tx, job)
+		tests.PanicOnErr(err)
+		assert.True(t, locked)
+
+		NewScheduler(clock.Now, job).RunDue()
+
+		assert.Zero(t, *runs)
+		_, err = GetRun("test")
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Failure recorded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		failing, _ := makeCountingJob("failing", errors.New("test failure"))
+		other, runs := makeCountingJob("other", nil)
+
+		NewScheduler(clock.Now, failing, other).RunDue()
+
+		run, err := GetRun("failing")
+		assert.NoError(t, err)
+		assert.False(t, run.Succeeded)
+		assert.Equal(t, "test failure", run.Message)
+		assert.Equal(t, 1, *runs)
+		list, err := GetRuns()
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+}
+
+func TestMarkReleasedJob(t *testing.T) {
+	t.Run("Passed release dates marked", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platformId := tests.GetRandomUuid()
+		_, err := db.Exec(`insert into platforms (id, name, short_name, user_id) values ($1, 'PC', 'PC', $2)`, platformId, userId)
+		tests.PanicOnErr(err)
+		makeGame := func(releaseDate string, precision int) uuid.UUID {
+			id := tests.GetRandomUuid()
+			query := `insert into games (id, title, platform_id, owned, release_date, release_date_precision, released, user_id) values ($1, 'game', $2, false, $3::timestamptz, $4, false, $5)`
+			_, err := db.Exec(query, id, platformId, releaseDate, precision, userId)
+			tests.PanicOnErr(err)
+			return id
+		}
+		passedDay := makeGame("2026-03-01T00:00:00Z", 0)
+		futureDay := makeGame("2026-03-20T00:00:00Z", 0)
+		passedMonth := makeGame("2026-02-01T00:00:00Z", 1)
+		currentQuarter := makeGame("2026-01-01T00:00:00Z", 2)
+		clock := &testClock{now: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)}
+
+		NewScheduler(clock.Now, MarkReleasedJob).RunDue()
+
+		released := func(id uuid.UUID) bool {
+			row, err := db.QueryRow(`select released from games where id = $1`, id)
+			tests.PanicOnErr(err)
+			var value bool
+			tests.PanicOnErr(row.Scan(&value))
+			return value
+		}
+		assert.True(t, released(passedDay))
+		assert.False(t, released(futureDay))
+		assert.True(t, released(passedMonth))
+		assert.False(t, released(currentQuarter))
+		run, err := GetRun(MarkReleasedJob.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, "2 games marked as released", run.Message)
+	})
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/trash"
+	"time"
+)
+
+// NewPurgeTrashJob creates a job removing items of all users for good, once they have been in the trash for longer than the retention period.
+func NewPurgeTrashJob(retention time.Duration) *Job {
+	return &Job{
+		Name:     "purge-trash",
+		Interval: time.Hour,
+		Run: func(tx gotabase.Connector, now time.Time) (string, error) {
+			count, err := trash.PurgeTx(tx, now.Add(-retention))
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d items purged", count), nil
+		},
+	}
+}
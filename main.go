@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
 	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/auth"
 	"github.com/KowalskiPiotr98/ludivault/controllers"
 	"github.com/KowalskiPiotr98/ludivault/database"
+	"github.com/KowalskiPiotr98/ludivault/jobs"
 	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
 	"net/url"
 	"os"
+	"strconv"
+	"time"
 )
 
 func init() {
@@ -38,6 +43,13 @@ func main() {
 		log.Panicf("Failed to apply database migrations: %v", err)
 	}
 
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Panicf("Command failed: %v", err)
+		}
+		return
+	}
+
 	if err := runEngine(); err != nil {
 		log.Panicf("Server failed while listening: %v", err)
 	}
@@ -65,6 +77,16 @@ func runEngine() error {
 		log.Panicf("Failed to setup login providers: %v", err)
 	}
 
+	retention := utils.GetOptionalConfig("trash_retention_days", "30")
+	retentionDays, err := strconv.Atoi(retention)
+	if err != nil || retentionDays < 1 {
+		log.Panicf("Invalid trash retention days: %s", retention)
+	}
+	scheduler := jobs.NewScheduler(time.Now, jobs.MarkReleasedJob, jobs.NewPurgeTrashJob(time.Duration(retentionDays)*24*time.Hour))
+	scheduler.Start(context.Background())
+	dispatcher := webhooks.NewDispatcher(time.Now)
+	dispatcher.Start(context.Background())
+
 	log.Infoln("Starting server...")
 	return router.Run(listenAddress)
 }
@@ -0,0 +1,26 @@
+package platforms
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+)
+
+var (
+	NameAlreadyUsedErr      = fmt.Errorf("%w: another platform already uses this name", operations.Errors.DataAlreadyExistErr)
+	ShortNameAlreadyUsedErr = fmt.Errorf("%w: another platform already uses this short name", operations.Errors.DataAlreadyExistErr)
+	MergeIntoSelfErr        = errors.New("platform cannot be merged into itself")
+)
+
+// InUseError is returned when a platform cannot be deleted, because some games still use it.
+type InUseError struct {
+	GameCount int
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("platform is used by %d games", e.GameCount)
+}
+
+func (e *InUseError) Unwrap() error {
+	return operations.Errors.DataUsedErr
+}
@@ -0,0 +1,70 @@
+package platforms
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"strings"
+	"unicode/utf8"
+)
+
+var noShortNameErr = errors.New("no unique short name could be derived")
+
+// FindByName returns the platform from the list with a matching name, or short name, ignoring case.
+// Matches by name are preferred, nil is returned if nothing matches.
+func FindByName(list []*Platform, name string) *Platform {
+	var shortNameMatch *Platform
+	for _, candidate := range list {
+		if strings.EqualFold(candidate.Name, name) {
+			return candidate
+		}
+		if shortNameMatch == nil && strings.EqualFold(candidate.ShortName, name) {
+			shortNameMatch = candidate
+		}
+	}
+	return shortNameMatch
+}
+
+// CreateByNameTx creates a platform with the name, and a short name derived from it,
+// that does not clash with any of the existing platforms of the user.
+func CreateByNameTx(connector gotabase.Connector, existing []*Platform, name string, userId uuid.UUID) (*Platform, error) {
+	shortName, err := deriveShortName(existing, name)
+	if err != nil {
+		return nil, err
+	}
+
+	platform := &Platform{Name: name, ShortName: shortName}
+	if err = CreatePlatformTx(connector, platform, userId); err != nil {
+		return nil, err
+	}
+	return platform, nil
+}
+
+// deriveShortName shortens the name to fit the short name column, adding a number if the result is already used.
+func deriveShortName(existing []*Platform, name string) (string, error) {
+	shortName := truncate(name, 5)
+	for suffix := 2; isShortNameUsed(existing, shortName); suffix++ {
+		if suffix > 9 {
+			return "", noShortNameErr
+		}
+		shortName = fmt.Sprintf("%s%d", truncate(name, 4), suffix)
+	}
+	return shortName, nil
+}
+
+func isShortNameUsed(existing []*Platform, shortName string) bool {
+	for _, platform := range existing {
+		if strings.EqualFold(platform.ShortName, shortName) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length])
+}
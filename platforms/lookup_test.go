@@ -0,0 +1,62 @@
+package platforms
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFindByName(t *testing.T) {
+	list := []*Platform{
+		{Name: "Switch", ShortName: "NS"},
+		{Name: "NS", ShortName: "NS2"},
+		{Name: "PlayStation 5", ShortName: "PS5"},
+	}
+
+	assert.Equal(t, list[0], FindByName(list, "switch"))
+	assert.Equal(t, list[1], FindByName(list, "ns"))
+	assert.Equal(t, list[2], FindByName(list, "ps5"))
+	assert.Nil(t, FindByName(list, "PC"))
+}
+
+func TestDeriveShortName(t *testing.T) {
+	t.Run("Short name unchanged", func(t *testing.T) {
+		shortName, err := deriveShortName(nil, "PC")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PC", shortName)
+	})
+
+	t.Run("Long name truncated", func(t *testing.T) {
+		shortName, err := deriveShortName(nil, "Nintendo Switch")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Ninte", shortName)
+	})
+
+	t.Run("Number added to avoid conflicts", func(t *testing.T) {
+		existing := []*Platform{{ShortName: "ninte"}, {ShortName: "Nint2"}}
+
+		shortName, err := deriveShortName(existing, "Nintendo 64")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Nint3", shortName)
+	})
+
+	t.Run("Multi-byte characters kept whole", func(t *testing.T) {
+		shortName, err := deriveShortName(nil, "ファミリーコンピュータ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ファミリー", shortName)
+	})
+
+	t.Run("No short name left", func(t *testing.T) {
+		existing := []*Platform{{ShortName: "Ninte"}}
+		for i := 2; i <= 9; i++ {
+			existing = append(existing, &Platform{ShortName: "Nint" + string(rune('0'+i))})
+		}
+
+		_, err := deriveShortName(existing, "Nintendo")
+
+		assert.Equal(t, noShortNameErr, err)
+	})
+}
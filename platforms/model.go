@@ -1,6 +1,7 @@
 package platforms
 
 import (
+	"database/sql"
 	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/google/uuid"
 )
@@ -9,6 +10,15 @@ type Platform struct {
 	Id        uuid.UUID
 	Name      string
 	ShortName string
+
+	// CatalogPlatformId is set for platforms adopted from the shared catalog.
+	// Manufacturer and ReleaseYear are only available for those platforms.
+	CatalogPlatformId uuid.NullUUID
+	Manufacturer      sql.NullString
+	ReleaseYear       sql.NullInt32
+
+	// Version is increased with every change of the platform.
+	Version int
 }
 
 func (p *Platform) SetId(id uuid.UUID) {
@@ -17,6 +27,10 @@ func (p *Platform) SetId(id uuid.UUID) {
 
 func scanPlatform(row gotabase.Row) (*Platform, error) {
 	var platform Platform
-	err := row.Scan(&platform.Id, &platform.Name, &platform.ShortName)
+	err := row.Scan(&platform.Id, &platform.Name, &platform.ShortName, &platform.CatalogPlatformId, &platform.Manufacturer, &platform.ReleaseYear, &platform.Version)
 	return &platform, err
 }
+
+func scanIdAndVersion(row gotabase.Row, platform *Platform) error {
+	return row.Scan(&platform.Id, &platform.Version)
+}
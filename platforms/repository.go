@@ -1,36 +1,241 @@
 package platforms
 
 import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/utils"
 	"github.com/google/uuid"
 )
 
 // GetPlatforms returns a complete list of [Platform] items from the database.
 func GetPlatforms(userId uuid.UUID) ([]*Platform, error) {
-	query := `select id, name, short_name from platforms where user_id = $1 order by name`
-	return operations.QueryRows(getDatabase(), scanPlatform, query, userId)
+	return GetPlatformsTx(getDatabase(), userId)
+}
+
+// GetPlatformsTx works like GetPlatforms, but uses the provided connector, so that it can be run in a transaction.
+func GetPlatformsTx(connector gotabase.Connector, userId uuid.UUID) ([]*Platform, error) {
+	query := `select p.id, p.name, p.short_name, p.catalog_platform_id, c.manufacturer, c.release_year, p.version from platforms p left join catalog_platforms c on c.id = p.catalog_platform_id where p.user_id = $1 and p.deleted_at is null order by p.name`
+	return operations.QueryRows(connector, scanPlatform, query, userId)
 }
 
 // GetPlatform returns a single [Platform] based on the id provided.
 func GetPlatform(id uuid.UUID, userId uuid.UUID) (*Platform, error) {
-	query := `select id, name, short_name from platforms where id = $1 and user_id = $2`
+	query := `select p.id, p.name, p.short_name, p.catalog_platform_id, c.manufacturer, c.release_year, p.version from platforms p left join catalog_platforms c on c.id = p.catalog_platform_id where p.id = $1 and p.user_id = $2 and p.deleted_at is null`
 	return operations.QueryRow(getDatabase(), scanPlatform, query, id, userId)
 }
 
+// AdoptPlatform adds the platform from the shared catalog to the list of platforms of the user.
+// The adopted [Platform] can be renamed by the user without affecting the catalog.
+//
+// If the catalog platform was already adopted by the user, the existing Platform is returned instead.
+func AdoptPlatform(catalogId uuid.UUID, userId uuid.UUID) (*Platform, error) {
+	query := `select p.id, p.name, p.short_name, p.catalog_platform_id, c.manufacturer, c.release_year, p.version from platforms p join catalog_platforms c on c.id = p.catalog_platform_id where p.catalog_platform_id = $1 and p.user_id = $2 and p.deleted_at is null`
+	existing, err := operations.QueryRow(getDatabase(), scanPlatform, query, catalogId, userId)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, operations.Errors.DataNotFoundErr) {
+		return nil, err
+	}
+
+	query = `select id, name, short_name, id, manufacturer, release_year, 0 from catalog_platforms where id = $1`
+	platform, err := operations.QueryRow(getDatabase(), scanPlatform, query, catalogId)
+	if err != nil {
+		return nil, err
+	}
+	// the catalog id is only used as the reference, the adopted platform will get a new one
+	platform.Id = uuid.Nil
+
+	if err = checkConflicts(getDatabase(), platform, userId); err != nil {
+		return nil, err
+	}
+
+	query = `insert into platforms (name, short_name, user_id, catalog_platform_id) values ($1, $2, $3, $4) returning id, version`
+	if err = operations.CreateRowWithScan(getDatabase(), platform, scanIdAndVersion, query, platform.Name, platform.ShortName, userId, platform.CatalogPlatformId); err != nil {
+		return nil, err
+	}
+	return platform, nil
+}
+
+// ResolvePlatformId returns the id of the user [Platform] matching the id provided.
+// The id can either point to a platform of the user or to a platform from the shared catalog.
+// In the latter case, the catalog platform is adopted by the user if that did not happen before.
+func ResolvePlatformId(id uuid.UUID, userId uuid.UUID) (uuid.UUID, error) {
+	query := `select id from platforms where user_id = $2 and (id = $1 or catalog_platform_id = $1) and deleted_at is null`
+	row, err := getDatabase().QueryRow(query, id, userId)
+	if err != nil {
+		return uuid.Nil, operations.Errors.HandleError(err)
+	}
+
+	var platformId uuid.UUID
+	err = operations.Errors.HandleError(row.Scan(&platformId))
+	if err == nil {
+		return platformId, nil
+	}
+	if !errors.Is(err, operations.Errors.DataNotFoundErr) {
+		return uuid.Nil, err
+	}
+
+	platform, err := AdoptPlatform(id, userId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return platform.Id, nil
+}
+
 // CreatePlatform creates a new [Platform] in the database and sets the id in the provided struct.
 func CreatePlatform(platform *Platform, userId uuid.UUID) error {
-	query := `insert into platforms (name, short_name, user_id) values ($1, $2, $3) returning id`
-	return operations.CreateRowWithId(getDatabase(), platform, query, platform.Name, platform.ShortName, userId)
+	return CreatePlatformTx(getDatabase(), platform, userId)
+}
+
+// CreatePlatformTx works like CreatePlatform, but uses the provided connector, so that it can be run in a transaction.
+//
+// Names and short names of platforms must be unique for each user.
+// NameAlreadyUsedErr or ShortNameAlreadyUsedErr is returned otherwise.
+func CreatePlatformTx(connector gotabase.Connector, platform *Platform, userId uuid.UUID) error {
+	if err := checkConflicts(connector, platform, userId); err != nil {
+		return err
+	}
+
+	query := `insert into platforms (name, short_name, user_id) values ($1, $2, $3) returning id, version`
+	return operations.CreateRowWithScan(connector, platform, scanIdAndVersion, query, platform.Name, platform.ShortName, userId)
 }
 
-// UpdatePlatform updates values of the [Platform] with the id as provided.
+// UpdatePlatform updates values of the [Platform] with the id as provided, and sets its new version.
+//
+// Names and short names of platforms must be unique for each user.
+// NameAlreadyUsedErr or ShortNameAlreadyUsedErr is returned otherwise.
+//
+// If the version of the platform is set, the platform is only updated if it still has that version, VersionMismatchErr from utils is returned otherwise.
 func UpdatePlatform(platform *Platform, userId uuid.UUID) error {
-	query := `update platforms set name = $3, short_name = $4 where id = $1 and user_id = $2`
-	return operations.UpdateRow(getDatabase(), query, platform.Id, userId, platform.Name, platform.ShortName)
+	if err := checkConflicts(getDatabase(), platform, userId); err != nil {
+		return err
+	}
+
+	query := `update platforms set name = $3, short_name = $4 where id = $1 and user_id = $2 and deleted_at is null and ($5::integer = 0 or version = $5) returning version`
+	version, err := utils.UpdateVersionedRow(getDatabase(), query, platform.Id, userId, platform.Name, platform.ShortName, platform.Version)
+	if err != nil {
+		return utils.CheckVersionMismatch(err, platform.Version, func() bool { return exists(getDatabase(), platform.Id, userId) })
+	}
+	platform.Version = version
+	return nil
+}
+
+// DeletePlatform moves a single [Platform] with the id provided to the trash.
+//
+// Platforms used by games cannot be deleted, in which case an [InUseError] with the number of those games is returned.
+// Use MergePlatform to reassign the games to another platform before deleting it.
+// Games in the trash do not prevent deleting their platform, and restoring them restores the platform as well.
+//
+// If the version is not 0, the platform is only deleted if it still has that version, VersionMismatchErr from utils is returned otherwise.
+func DeletePlatform(id uuid.UUID, version int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		// lock the platform, so that games being added to it have to wait for the outcome
+		query := `select id from platforms where id = $1 and user_id = $2 and deleted_at is null and ($3::integer = 0 or version = $3) for update`
+		if _, err := operations.QueryRow(tx, scanId, query, id, userId, version); err != nil {
+			return utils.CheckVersionMismatch(err, version, func() bool { return exists(tx, id, userId) })
+		}
+
+		count, err := countGames(tx, id, userId)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return &InUseError{GameCount: count}
+		}
+
+		query = `update platforms set deleted_at = now() where id = $1 and user_id = $2`
+		return operations.UpdateRow(tx, query, id, userId)
+	})
+}
+
+// MergePlatform reassigns all games from one [Platform] to the target one and moves the now unused platform to the trash.
+// Games in the trash are reassigned as well, but only the number of the remaining reassigned games is returned.
+//
+// If the version is not 0, the platform is only merged if it still has that version, VersionMismatchErr from utils is returned otherwise.
+func MergePlatform(id uuid.UUID, version int, targetId uuid.UUID, userId uuid.UUID) (int, error) {
+	if id == targetId {
+		return 0, MergeIntoSelfErr
+	}
+
+	var moved int
+	err := utils.RunInTransaction(func(tx gotabase.Connector) error {
+		// lock the target, so that it cannot be removed before the games are moved
+		query := `select id from platforms where id = $1 and user_id = $2 and deleted_at is null for update`
+		if _, err := operations.QueryRow(tx, scanId, query, targetId, userId); err != nil {
+			return err
+		}
+
+		query = `with moved as (update games set platform_id = $2 where platform_id = $1 and user_id = $3 returning deleted_at)
+			select count(1) filter (where deleted_at is null) from moved`
+		row, err := tx.QueryRow(query, id, targetId, userId)
+		if err != nil {
+			return operations.Errors.HandleError(err)
+		}
+		if err = row.Scan(&moved); err != nil {
+			return operations.Errors.HandleError(err)
+		}
+
+		query = `update platforms set deleted_at = now() where id = $1 and user_id = $2 and deleted_at is null and ($3::integer = 0 or version = $3)`
+		if err = operations.UpdateRow(tx, query, id, userId, version); err != nil {
+			return utils.CheckVersionMismatch(err, version, func() bool { return exists(tx, id, userId) })
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+func exists(connector gotabase.Connector, id uuid.UUID, userId uuid.UUID) bool {
+	query := `select id from platforms where id = $1 and user_id = $2 and deleted_at is null`
+	_, err := operations.QueryRow(connector, scanId, query, id, userId)
+	return err == nil
+}
+
+func countGames(connector gotabase.Connector, id uuid.UUID, userId uuid.UUID) (int, error) {
+	query := `select count(1) from games where platform_id = $1 and user_id = $2 and deleted_at is null`
+	row, err := connector.QueryRow(query, id, userId)
+	if err != nil {
+		return 0, operations.Errors.HandleError(err)
+	}
+	var count int
+	if err = row.Scan(&count); err != nil {
+		return 0, operations.Errors.HandleError(err)
+	}
+	return count, nil
+}
+
+// checkConflicts returns an error describing which value of the [Platform] is already used by another platform of the user.
+// The unique constraints are still enforced by the database, so concurrent changes can result in a generic DataAlreadyExistErr instead.
+func checkConflicts(connector gotabase.Connector, platform *Platform, userId uuid.UUID) error {
+	query := `select exists(select from platforms where user_id = $1 and id <> $2 and name = $3 and deleted_at is null),
+		exists(select from platforms where user_id = $1 and id <> $2 and short_name = $4 and deleted_at is null)`
+	row, err := connector.QueryRow(query, userId, platform.Id, platform.Name, platform.ShortName)
+	if err != nil {
+		return operations.Errors.HandleError(err)
+	}
+
+	var nameUsed, shortNameUsed bool
+	if err = row.Scan(&nameUsed, &shortNameUsed); err != nil {
+		return operations.Errors.HandleError(err)
+	}
+
+	if nameUsed {
+		return NameAlreadyUsedErr
+	}
+	if shortNameUsed {
+		return ShortNameAlreadyUsedErr
+	}
+	return nil
 }
 
-// DeletePlatform removes a single [Platform] with the id provided from the database.
-func DeletePlatform(id uuid.UUID, userId uuid.UUID) error {
-	query := `delete from platforms where id = $1 and user_id = $2`
-	return operations.DeleteRow(getDatabase(), query, id, userId)
+func scanId(row gotabase.Row) (*uuid.UUID, error) {
+	var id uuid.UUID
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
 }
@@ -3,6 +3,8 @@ package platforms
 import (
 	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -22,10 +24,10 @@ func TestCreatePlatform(t *testing.T) {
 		err := CreatePlatform(&newPlatform, tests.MakeTestUserId(getDatabase()))
 
 		assert.NoError(t, err)
-		dbRow, err := getDatabase().QueryRow("select id, name, short_name from platforms where id = $1", newPlatform.Id)
+		dbRow, err := getDatabase().QueryRow("select id, name, short_name, version from platforms where id = $1", newPlatform.Id)
 		tests.PanicOnErr(err)
 		var dbPlatform Platform
-		tests.PanicOnErr(dbRow.Scan(&dbPlatform.Id, &dbPlatform.Name, &dbPlatform.ShortName))
+		tests.PanicOnErr(dbRow.Scan(&dbPlatform.Id, &dbPlatform.Name, &dbPlatform.ShortName, &dbPlatform.Version))
 		assert.Equal(t, newPlatform, dbPlatform)
 	})
 
@@ -37,11 +39,52 @@ func TestCreatePlatform(t *testing.T) {
 
 		err := CreatePlatform(&newPlatform, userId)
 
-		assert.Equal(t, operations.Errors.DataAlreadyExistErr, err)
+		assert.Equal(t, NameAlreadyUsedErr, err)
+		assert.ErrorIs(t, err, operations.Errors.DataAlreadyExistErr)
+	})
+
+	t.Run("Short name already used", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(CreatePlatform(&Platform{Name: "first", ShortName: "tp"}, userId))
+
+		err := CreatePlatform(&Platform{Name: "second", ShortName: "tp"}, userId)
+
+		assert.Equal(t, ShortNameAlreadyUsedErr, err)
+	})
+
+	t.Run("Same platform created by two users", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		firstPlatform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&firstPlatform, tests.MakeTestUserId(getDatabase())))
+		secondPlatform := makeTestDefaultPlatform()
+
+		err := CreatePlatform(&secondPlatform, tests.MakeTestUserId(getDatabase()))
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, firstPlatform.Id, secondPlatform.Id)
 	})
 }
 
 func TestGetPlatforms(t *testing.T) {
+	t.Run("Two users with identical platforms", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		firstUserId := tests.MakeTestUserId(getDatabase())
+		secondUserId := tests.MakeTestUserId(getDatabase())
+		firstPlatform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&firstPlatform, firstUserId))
+		secondPlatform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&secondPlatform, secondUserId))
+
+		firstList, err := GetPlatforms(firstUserId)
+		assert.NoError(t, err)
+		secondList, err := GetPlatforms(secondUserId)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []*Platform{&firstPlatform}, firstList)
+		assert.Equal(t, []*Platform{&secondPlatform}, secondList)
+	})
+
 	t.Run("Get all platforms", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
 		userId := tests.MakeTestUserId(getDatabase())
@@ -157,7 +200,25 @@ func TestUpdatePlatform(t *testing.T) {
 
 		err := UpdatePlatform(&platform2, userId)
 
-		assert.Equal(t, operations.Errors.DataAlreadyExistErr, err)
+		assert.Equal(t, NameAlreadyUsedErr, err)
+	})
+
+	t.Run("Platform of another user with the same name - updated", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		otherPlatform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&otherPlatform, tests.MakeTestUserId(getDatabase())))
+		userId := tests.MakeTestUserId(getDatabase())
+		platform := Platform{Name: "my platform", ShortName: "mp"}
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		platform.Name = otherPlatform.Name
+		platform.ShortName = otherPlatform.ShortName
+
+		err := UpdatePlatform(&platform, userId)
+
+		assert.NoError(t, err)
+		dbPlatform, err := GetPlatform(platform.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, platform, *dbPlatform)
 	})
 
 	t.Run("User not authorised", func(t *testing.T) {
@@ -172,6 +233,22 @@ func TestUpdatePlatform(t *testing.T) {
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
+
+	t.Run("Version changed in the meantime - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		other := platform
+		other.Name = "other device"
+		tests.PanicOnErr(UpdatePlatform(&other, userId))
+		platform.Name = "updated platform"
+
+		err := UpdatePlatform(&platform, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+		assert.Equal(t, 2, other.Version)
+	})
 }
 
 func TestDeletePlatform(t *testing.T) {
@@ -181,7 +258,7 @@ func TestDeletePlatform(t *testing.T) {
 		userId := tests.MakeTestUserId(getDatabase())
 		tests.PanicOnErr(CreatePlatform(&platform, userId))
 
-		err := DeletePlatform(platform.Id, userId)
+		err := DeletePlatform(platform.Id, 0, userId)
 
 		assert.NoError(t, err)
 		_, err = GetPlatform(platform.Id, userId)
@@ -192,7 +269,7 @@ func TestDeletePlatform(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
 		userId := tests.MakeTestUserId(getDatabase())
 
-		err := DeletePlatform(tests.GetRandomUuid(), userId)
+		err := DeletePlatform(tests.GetRandomUuid(), 0, userId)
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -203,7 +280,223 @@ func TestDeletePlatform(t *testing.T) {
 		userId := tests.MakeTestUserId(getDatabase())
 		tests.PanicOnErr(CreatePlatform(&platform, userId))
 
-		err := DeletePlatform(platform.Id, tests.MakeTestUserId(getDatabase()))
+		err := DeletePlatform(platform.Id, 0, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Platform used by games - error with game count", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		platform := makeTestDefaultPlatform()
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		makeGame(platform.Id, userId)
+		makeGame(platform.Id, userId)
+
+		err := DeletePlatform(platform.Id, 0, userId)
+
+		assert.Equal(t, &InUseError{GameCount: 2}, err)
+		assert.ErrorIs(t, err, operations.Errors.DataUsedErr)
+	})
+
+	t.Run("Games in trash ignored", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		platform := makeTestDefaultPlatform()
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		gameId := makeGame(platform.Id, userId)
+		_, err := getDatabase().Exec(`update games set deleted_at = now() where id = $1`, gameId)
+		tests.PanicOnErr(err)
+
+		err = DeletePlatform(platform.Id, 0, userId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Name of deleted platform can be reused", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		platform := makeTestDefaultPlatform()
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		tests.PanicOnErr(DeletePlatform(platform.Id, 0, userId))
+
+		recreated := makeTestDefaultPlatform()
+		err := CreatePlatform(&recreated, userId)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, platform.Id, recreated.Id)
+	})
+
+	t.Run("Version changed in the meantime - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		platform := makeTestDefaultPlatform()
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+		version := platform.Version
+		platform.Name = "updated platform"
+		tests.PanicOnErr(UpdatePlatform(&platform, userId))
+
+		err := DeletePlatform(platform.Id, version, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+		_, err = GetPlatform(platform.Id, userId)
+		assert.NoError(t, err)
+	})
+}
+
+func makeGame(platformId uuid.UUID, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into games (id, title, platform_id, released, user_id) values ($1, 'game', $2, true, $3)`
+	_, err := getDatabase().Exec(query, id, platformId, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func TestMergePlatform(t *testing.T) {
+	t.Run("Games moved and platform deleted", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		source := Platform{Name: "source", ShortName: "src"}
+		tests.PanicOnErr(CreatePlatform(&source, userId))
+		target := Platform{Name: "target", ShortName: "tgt"}
+		tests.PanicOnErr(CreatePlatform(&target, userId))
+		gameId := makeGame(source.Id, userId)
+		makeGame(source.Id, userId)
+
+		moved, err := MergePlatform(source.Id, 0, target.Id, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, moved)
+		_, err = GetPlatform(source.Id, userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		row, err := getDatabase().QueryRow("select platform_id from games where id = $1", gameId)
+		tests.PanicOnErr(err)
+		var platformId uuid.UUID
+		tests.PanicOnErr(row.Scan(&platformId))
+		assert.Equal(t, target.Id, platformId)
+	})
+
+	t.Run("Target of another user - nothing changed", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		source := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&source, userId))
+		makeGame(source.Id, userId)
+		target := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&target, tests.MakeTestUserId(getDatabase())))
+
+		_, err := MergePlatform(source.Id, 0, target.Id, userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		_, err = GetPlatform(source.Id, userId)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Merge into itself - error", func(t *testing.T) {
+		id := tests.GetRandomUuid()
+
+		_, err := MergePlatform(id, 0, id, tests.GetRandomUuid())
+
+		assert.Equal(t, MergeIntoSelfErr, err)
+	})
+}
+
+func getTestCatalogPlatform() (uuid.UUID, string) {
+	row, err := getDatabase().QueryRow("select id, name from catalog_platforms where short_name = 'PS5'")
+	tests.PanicOnErr(err)
+	var id uuid.UUID
+	var name string
+	tests.PanicOnErr(row.Scan(&id, &name))
+	return id, name
+}
+
+func TestAdoptPlatform(t *testing.T) {
+	t.Run("Catalog platform adopted", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		catalogId, name := getTestCatalogPlatform()
+
+		platform, err := AdoptPlatform(catalogId, userId)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, catalogId, platform.Id)
+		assert.Equal(t, name, platform.Name)
+		assert.Equal(t, uuid.NullUUID{Valid: true, UUID: catalogId}, platform.CatalogPlatformId)
+		list, err := GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, []*Platform{platform}, list)
+	})
+
+	t.Run("Adopting twice returns the same platform", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		catalogId, _ := getTestCatalogPlatform()
+		first, err := AdoptPlatform(catalogId, userId)
+		tests.PanicOnErr(err)
+
+		second, err := AdoptPlatform(catalogId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, first.Id, second.Id)
+	})
+
+	t.Run("Renamed adopted platform keeps catalog reference", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		catalogId, _ := getTestCatalogPlatform()
+		platform, err := AdoptPlatform(catalogId, userId)
+		tests.PanicOnErr(err)
+		platform.Name = "my console"
+
+		tests.PanicOnErr(UpdatePlatform(platform, userId))
+
+		dbPlatform, err := GetPlatform(platform.Id, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, "my console", dbPlatform.Name)
+		assert.Equal(t, catalogId, dbPlatform.CatalogPlatformId.UUID)
+	})
+
+	t.Run("Catalog platform does not exist - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+
+		_, err := AdoptPlatform(tests.GetRandomUuid(), tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestResolvePlatformId(t *testing.T) {
+	t.Run("User platform id returned as is", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&platform, userId))
+
+		id, err := ResolvePlatformId(platform.Id, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, platform.Id, id)
+	})
+
+	t.Run("Catalog platform id resolved to adopted platform", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		catalogId, _ := getTestCatalogPlatform()
+
+		id, err := ResolvePlatformId(catalogId, userId)
+
+		assert.NoError(t, err)
+		platform, err := GetPlatform(id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, catalogId, platform.CatalogPlatformId.UUID)
+	})
+
+	t.Run("Platform of another user - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		platform := makeTestDefaultPlatform()
+		tests.PanicOnErr(CreatePlatform(&platform, tests.MakeTestUserId(getDatabase())))
+
+		_, err := ResolvePlatformId(platform.Id, tests.MakeTestUserId(getDatabase()))
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -0,0 +1,7 @@
+package playnite
+
+import "errors"
+
+var (
+	InvalidFileErr = errors.New("playnite library contains invalid data")
+)
@@ -0,0 +1,128 @@
+package playnite
+
+import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"io"
+	"time"
+)
+
+// gameKey identifies a game by its platform and normalised title.
+type gameKey struct {
+	platformId uuid.UUID
+	title      string
+}
+
+// Import creates games, and their playthroughs, from a library exported from Playnite in a single transaction.
+//
+// Platforms are matched by name or short name, and the ones that do not exist yet are created.
+// Games that already exist on the platform with the same title are skipped, so the import can be repeated.
+// Playthroughs are only created for games with a completion status mapped in statusMapping,
+// with the playtime of the game as their runtime.
+// Fields and statuses which could not be imported are counted in the result.
+// If any game is invalid, nothing is created and InvalidFileErr is returned along with the errors found.
+//
+// In dry-run mode all changes are made and then rolled back, so that the result shows what would be created.
+func Import(reader io.Reader, dryRun bool, userId uuid.UUID) (*Result, error) {
+	result := &Result{
+		DryRun:           dryRun,
+		PlatformsCreated: make([]string, 0),
+		Games:            make([]*GamePreview, 0),
+		Errors:           make([]*GameError, 0),
+		UnmappedFields:   make(map[string]int),
+		UnmappedStatuses: make(map[string]int),
+	}
+
+	entries := parse(reader, time.Now(), result)
+	if len(result.Errors) > 0 {
+		return result, InvalidFileErr
+	}
+
+	userGames, err := games.GetAllGames(userId)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[gameKey]bool, len(userGames))
+	for _, game := range userGames {
+		known[gameKey{platformId: game.PlatformId, title: games.NormaliseTitle(game.Title)}] = true
+	}
+
+	err = utils.RunInTransactionWithDryRun(dryRun, func(tx gotabase.Connector) error {
+		return importEntries(tx, entries, known, userId, result)
+	})
+	if errors.Is(err, InvalidFileErr) {
+		// the transaction was rolled back, so nothing was created after all
+		result.PlatformsCreated = make([]string, 0)
+		result.GamesCreated = 0
+		result.GamesSkipped = 0
+		result.PlaythroughsCreated = 0
+		result.Games = make([]*GamePreview, 0)
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func importEntries(tx gotabase.Connector, entries []*entry, known map[gameKey]bool, userId uuid.UUID, result *Result) error {
+	existing, err := platforms.GetPlatformsTx(tx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		platform := platforms.FindByName(existing, entry.platform)
+		newPlatform := platform == nil
+		if newPlatform {
+			if platform, err = platforms.CreateByNameTx(tx, existing, entry.platform, userId); err != nil {
+				result.addError(entry.index, entry.game.Title, "failed to create platform %q: %v", entry.platform, err)
+				return InvalidFileErr
+			}
+			existing = append(existing, platform)
+			result.PlatformsCreated = append(result.PlatformsCreated, platform.Name)
+		}
+
+		preview := &GamePreview{
+			Index:       entry.index,
+			Title:       entry.game.Title,
+			Platform:    platform.Name,
+			NewPlatform: newPlatform,
+		}
+		result.Games = append(result.Games, preview)
+
+		key := gameKey{platformId: platform.Id, title: games.NormaliseTitle(entry.game.Title)}
+		if known[key] {
+			preview.Skipped = true
+			result.GamesSkipped++
+			continue
+		}
+		known[key] = true
+
+		entry.game.PlatformId = platform.Id
+		if err = games.CreateGameTx(tx, entry.game, userId); err != nil {
+			return err
+		}
+		result.GamesCreated++
+
+		if entry.playthrough != nil {
+			entry.playthrough.GameId = entry.game.Id
+			if err = playthroughs.CreatePlaythroughTx(tx, entry.playthrough, userId); err != nil {
+				return err
+			}
+			result.PlaythroughsCreated++
+			preview.Playthrough = &PlaythroughPreview{
+				Status:  entry.playthrough.Status,
+				Runtime: int(entry.playthrough.Runtime.Int32),
+			}
+		}
+	}
+
+	return nil
+}
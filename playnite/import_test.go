@@ -0,0 +1,94 @@
+package playnite
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func importFixture(dryRun bool, userId uuid.UUID) (*Result, error) {
+	file, err := os.Open("testdata/library.json")
+	tests.PanicOnErr(err)
+	defer file.Close()
+	return Import(file, dryRun, userId)
+}
+
+func TestImport(t *testing.T) {
+	t.Run("Games imported", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		existing := &platforms.Platform{Name: "GOG", ShortName: "GOG"}
+		tests.PanicOnErr(platforms.CreatePlatform(existing, userId))
+
+		result, err := importFixture(false, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"PC (Windows)"}, result.PlatformsCreated)
+		assert.Equal(t, 4, result.GamesCreated)
+		assert.Equal(t, 2, result.PlaythroughsCreated)
+		assert.False(t, result.Games[1].NewPlatform)
+		assert.Equal(t, playthroughs.PlaythroughCompleted, result.Games[0].Playthrough.Status)
+		assert.Equal(t, map[string]int{"Played": 1}, result.UnmappedStatuses)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 4)
+		userPlaythroughs, err := playthroughs.GetPlaythroughs(uuid.Nil, userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userPlaythroughs, 2)
+	})
+
+	t.Run("Import can be repeated", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		_, err := importFixture(false, userId)
+		tests.PanicOnErr(err)
+
+		result, err := importFixture(false, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.PlatformsCreated)
+		assert.Zero(t, result.GamesCreated)
+		assert.Equal(t, 4, result.GamesSkipped)
+		assert.Zero(t, result.PlaythroughsCreated)
+		assert.True(t, result.Games[0].Skipped)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 4)
+	})
+
+	t.Run("Dry run creates nothing", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := importFixture(true, userId)
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 4, result.GamesCreated)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+		userPlatforms, err := platforms.GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userPlatforms)
+	})
+
+	t.Run("Invalid library imports nothing", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := Import(strings.NewReader(`[{"Name": "Valid", "Platforms": ["PC"]}, {"Name": "Invalid"}]`), false, userId)
+
+		assert.ErrorIs(t, err, InvalidFileErr)
+		assert.Len(t, result.Errors, 1)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+	})
+}
@@ -0,0 +1,63 @@
+package playnite
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+)
+
+// Result summarises the changes made, or that would be made in dry-run mode, by Import.
+type Result struct {
+	DryRun              bool
+	PlatformsCreated    []string
+	GamesCreated        int
+	GamesSkipped        int
+	PlaythroughsCreated int
+	Games               []*GamePreview
+	Errors              []*GameError
+	// UnmappedFields counts the games, that had a value set in a field which is not imported.
+	UnmappedFields map[string]int
+	// UnmappedStatuses counts the games with a completion status, that has no matching playthrough status.
+	UnmappedStatuses map[string]int
+}
+
+// GamePreview describes what was created from a single game of the library.
+type GamePreview struct {
+	Index       int
+	Title       string
+	Platform    string
+	NewPlatform bool
+	// Skipped is set if the game already existed on the platform, in which case nothing was created.
+	Skipped     bool
+	Playthrough *PlaythroughPreview
+}
+
+// PlaythroughPreview describes the playthrough created for a game.
+type PlaythroughPreview struct {
+	Status  playthroughs.PlaythroughStatus
+	Runtime int
+}
+
+// GameError describes a problem with a single game of the library.
+// Index is the 1-based position of the game in the library.
+type GameError struct {
+	Index   int
+	Title   string
+	Message string
+}
+
+func (r *Result) addError(index int, title string, format string, args ...any) {
+	r.Errors = append(r.Errors, &GameError{
+		Index:   index,
+		Title:   title,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// entry is a single parsed game of the library.
+type entry struct {
+	index       int
+	platform    string
+	game        *games.Game
+	playthrough *playthroughs.Playthrough
+}
@@ -0,0 +1,328 @@
+package playnite
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Fields of a Playnite game, which are imported, matched case-insensitively.
+const (
+	fieldName             = "name"
+	fieldPlatforms        = "platforms"
+	fieldSource           = "source"
+	fieldPlaytime         = "playtime"
+	fieldCompletionStatus = "completionstatus"
+	fieldReleaseDate      = "releasedate"
+	fieldAdded            = "added"
+	fieldLastActivity     = "lastactivity"
+)
+
+// ignoredFields are identifiers internal to Playnite, which are neither imported nor reported as unmapped.
+var ignoredFields = map[string]bool{
+	"id":       true,
+	"gameid":   true,
+	"pluginid": true,
+}
+
+// statusMapping maps the default completion statuses of Playnite onto playthrough statuses, by their lower-case names:
+//   - "Playing" means the game is being played, so the playthrough is in progress,
+//   - "Beaten" and "Completed" mean the game was finished, either the main story or all of it, so the playthrough is completed,
+//   - "Abandoned" means the player gave up on the game, so the playthrough is dropped,
+//   - "On Hold" means the player intends to get back to the game, so the playthrough is suspended.
+//
+// "Not Played" and "Plan to Play" mean that there was no playthrough yet, so none is created (see noPlaythroughStatuses).
+// "Played" and custom statuses do not say whether the game was finished, so they are reported as unmapped instead of guessed.
+var statusMapping = map[string]playthroughs.PlaythroughStatus{
+	"playing":   playthroughs.PlaythroughInProgress,
+	"beaten":    playthroughs.PlaythroughCompleted,
+	"completed": playthroughs.PlaythroughCompleted,
+	"abandoned": playthroughs.PlaythroughDropped,
+	"on hold":   playthroughs.PlaythroughSuspended,
+}
+
+var noPlaythroughStatuses = map[string]bool{
+	"not played":   true,
+	"plan to play": true,
+}
+
+// timeLayouts are tried in order when reading dates and times, as exporters format them differently.
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// parse reads all games of the library, reporting any invalid values and unmapped data in the result.
+//
+// The library is a JSON array of games, or an object with such array in the "Games" field.
+// Each game must have a name, and a platform, taken from the first of its platforms, or from its source if it has none.
+// Names of platforms, sources and completion statuses can be given either as strings, or objects with a "Name" field.
+func parse(reader io.Reader, now time.Time, result *Result) []*entry {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		result.addError(0, "", "failed to read file: %v", err)
+		return nil
+	}
+
+	var library []map[string]json.RawMessage
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var wrapper struct {
+			Games []map[string]json.RawMessage
+		}
+		err = json.Unmarshal(data, &wrapper)
+		library = wrapper.Games
+	} else {
+		err = json.Unmarshal(data, &library)
+	}
+	if err != nil {
+		result.addError(0, "", "file is not a valid Playnite library: %v", err)
+		return nil
+	}
+
+	entries := make([]*entry, 0, len(library))
+	for i, fields := range library {
+		if parsed := parseGame(i+1, fields, now, result); parsed != nil {
+			entries = append(entries, parsed)
+		}
+	}
+	return entries
+}
+
+func parseGame(index int, fields map[string]json.RawMessage, now time.Time, result *Result) *entry {
+	var title, source, status string
+	var platformNames []string
+	var playtime uint64
+	var releaseDate, added, lastActivity sql.NullTime
+	var precision games.ReleaseDatePrecision
+	var playtimeField string
+
+	values := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		name := strings.ToLower(key)
+		values[name] = value
+		switch name {
+		case fieldName, fieldPlatforms, fieldSource, fieldCompletionStatus, fieldReleaseDate, fieldAdded, fieldLastActivity:
+		case fieldPlaytime:
+			playtimeField = key
+		default:
+			if !ignoredFields[name] && !isEmpty(value) {
+				result.UnmappedFields[key]++
+			}
+		}
+	}
+
+	// the name is read first, so that it can be included in the errors
+	title, err := readName(values[fieldName])
+	if err != nil || title == "" {
+		result.addError(index, "", "name is missing")
+		return nil
+	}
+
+	fail := func(field string, err error) *entry {
+		result.addError(index, title, "%s is not valid: %v", field, err)
+		return nil
+	}
+	if platformNames, err = readNames(values[fieldPlatforms]); err != nil {
+		return fail("platforms", err)
+	}
+	if source, err = readName(values[fieldSource]); err != nil {
+		return fail("source", err)
+	}
+	if status, err = readName(values[fieldCompletionStatus]); err != nil {
+		return fail("completion status", err)
+	}
+	if playtime, err = readPlaytime(values[fieldPlaytime]); err != nil {
+		return fail("playtime", err)
+	}
+	if releaseDate, precision, err = readReleaseDate(values[fieldReleaseDate]); err != nil {
+		return fail("release date", err)
+	}
+	if added, err = readTime(values[fieldAdded], timeLayouts); err != nil {
+		return fail("added", err)
+	}
+	if lastActivity, err = readTime(values[fieldLastActivity], timeLayouts); err != nil {
+		return fail("last activity", err)
+	}
+
+	parsed := &entry{index: index}
+	if len(platformNames) > 0 {
+		parsed.platform = platformNames[0]
+	} else {
+		parsed.platform = source
+	}
+	if parsed.platform == "" {
+		result.addError(index, title, "platform is missing")
+		return nil
+	}
+
+	// games in a Playnite library are owned and, unless their release date is in the future or to be announced, already released
+	parsed.game = &games.Game{
+		Title:                title,
+		Owned:                true,
+		ReleaseDate:          releaseDate,
+		ReleaseDatePrecision: precision,
+	}
+	parsed.game.Released = parsed.game.ReleaseDatePassed(now) || (!releaseDate.Valid && precision != games.ReleaseDateTBA)
+
+	normalisedStatus := strings.ToLower(status)
+	playthroughStatus, mapped := statusMapping[normalisedStatus]
+	if !mapped {
+		if status != "" && !noPlaythroughStatuses[normalisedStatus] {
+			result.UnmappedStatuses[status]++
+		}
+		if playtime > 0 {
+			// without a playthrough, there is nothing to add the playtime to
+			result.UnmappedFields[playtimeField]++
+		}
+		return parsed
+	}
+
+	parsed.playthrough = &playthroughs.Playthrough{
+		StartDate: startDate(added, lastActivity, now),
+		Status:    playthroughStatus,
+	}
+	if playtime > 0 {
+		parsed.playthrough.Runtime = sql.NullInt32{Valid: true, Int32: int32(playtime / 60)}
+	}
+	if playthroughStatus == playthroughs.PlaythroughCompleted || playthroughStatus == playthroughs.PlaythroughDropped {
+		parsed.playthrough.EndDate = sql.NullTime{Valid: true, Time: parsed.playthrough.StartDate}
+		if lastActivity.Valid && lastActivity.Time.After(parsed.playthrough.StartDate) {
+			parsed.playthrough.EndDate.Time = lastActivity.Time
+		}
+	}
+	return parsed
+}
+
+// startDate estimates when the playthrough started, as Playnite only tracks when the game was added and last played.
+func startDate(added sql.NullTime, lastActivity sql.NullTime, now time.Time) time.Time {
+	switch {
+	case added.Valid && lastActivity.Valid && lastActivity.Time.Before(added.Time):
+		return lastActivity.Time
+	case added.Valid:
+		return added.Time
+	case lastActivity.Valid:
+		return lastActivity.Time
+	default:
+		return now
+	}
+}
+
+// readName reads a name given either as a string, or an object with a "Name" field.
+func readName(value json.RawMessage) (string, error) {
+	if isNull(value) {
+		return "", nil
+	}
+
+	var name string
+	if err := json.Unmarshal(value, &name); err == nil {
+		return strings.TrimSpace(name), nil
+	}
+	var item struct {
+		Name string
+	}
+	if err := json.Unmarshal(value, &item); err != nil {
+		return "", errors.New("expected a name or an object with a name")
+	}
+	return strings.TrimSpace(item.Name), nil
+}
+
+// readNames reads a list of names, each given as described in readName, skipping empty ones.
+func readNames(value json.RawMessage) ([]string, error) {
+	if isNull(value) {
+		return nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err != nil {
+		return nil, errors.New("expected a list")
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name, err := readName(item)
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// readPlaytime reads the playtime in seconds.
+func readPlaytime(value json.RawMessage) (uint64, error) {
+	if isNull(value) {
+		return 0, nil
+	}
+
+	var playtime uint64
+	if err := json.Unmarshal(value, &playtime); err != nil {
+		return 0, errors.New("expected a non-negative number of seconds")
+	}
+	if playtime/60 > math.MaxInt32 {
+		return 0, fmt.Errorf("%d seconds is too long", playtime)
+	}
+	return playtime, nil
+}
+
+// readReleaseDate reads the release date, given either as a string, or an object with a "ReleaseDate" field.
+// Playnite allows partial release dates, such as "2019-10" or "2019", which are read with the matching precision.
+func readReleaseDate(value json.RawMessage) (sql.NullTime, games.ReleaseDatePrecision, error) {
+	var item struct {
+		ReleaseDate json.RawMessage
+	}
+	if err := json.Unmarshal(value, &item); err == nil && !isNull(item.ReleaseDate) {
+		value = item.ReleaseDate
+	}
+
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		if releaseDate, precision, ok := games.ParseReleaseDate(text); ok {
+			return releaseDate, precision, nil
+		}
+	}
+	releaseDate, err := readTime(value, timeLayouts)
+	return releaseDate, games.ReleaseDateDay, err
+}
+
+// readTime reads a time from a string in any of the layouts.
+// Times without a time zone are assumed to be UTC.
+func readTime(value json.RawMessage, layouts []string) (sql.NullTime, error) {
+	if isNull(value) {
+		return sql.NullTime{}, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return sql.NullTime{}, errors.New("expected a date")
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return sql.NullTime{}, nil
+	}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			return sql.NullTime{Valid: true, Time: parsed}, nil
+		}
+	}
+	return sql.NullTime{}, fmt.Errorf("%q is not a supported date", text)
+}
+
+func isNull(value json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// isEmpty checks if the value is null, or the zero value of its type.
+func isEmpty(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "", "null", `""`, "[]", "{}", "false", "0":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,135 @@
+package playnite
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func parseString(content string) ([]*entry, *Result) {
+	result := &Result{UnmappedFields: make(map[string]int), UnmappedStatuses: make(map[string]int)}
+	entries := parse(strings.NewReader(content), testNow, result)
+	return entries, result
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Reads fixture", func(t *testing.T) {
+		file, err := os.Open("testdata/library.json")
+		assert.NoError(t, err)
+		defer file.Close()
+		result := &Result{UnmappedFields: make(map[string]int), UnmappedStatuses: make(map[string]int)}
+
+		entries := parse(file, testNow, result)
+
+		assert.Empty(t, result.Errors)
+		assert.Len(t, entries, 4)
+
+		assert.Equal(t, "Hollow Knight", entries[0].game.Title)
+		assert.Equal(t, "PC (Windows)", entries[0].platform)
+		assert.True(t, entries[0].game.Owned)
+		assert.True(t, entries[0].game.Released)
+		assert.Equal(t, time.Date(2017, 2, 24, 0, 0, 0, 0, time.UTC), entries[0].game.ReleaseDate.Time)
+		assert.Equal(t, playthroughs.PlaythroughCompleted, entries[0].playthrough.Status)
+		assert.Equal(t, int32(2521), entries[0].playthrough.Runtime.Int32)
+		assert.True(t, entries[0].playthrough.StartDate.Equal(time.Date(2021, 3, 4, 11, 34, 56, 123456700, time.UTC)))
+		assert.Equal(t, time.Date(2021, 5, 1, 20, 0, 0, 0, time.UTC), entries[0].playthrough.EndDate.Time)
+
+		assert.Equal(t, "GOG", entries[1].platform)
+		assert.Equal(t, time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC), entries[1].game.ReleaseDate.Time)
+		assert.Equal(t, playthroughs.PlaythroughSuspended, entries[1].playthrough.Status)
+		assert.Equal(t, testNow, entries[1].playthrough.StartDate)
+		assert.False(t, entries[1].playthrough.EndDate.Valid)
+
+		assert.Nil(t, entries[2].playthrough)
+		assert.Nil(t, entries[3].playthrough)
+		assert.False(t, entries[3].game.Released)
+
+		assert.Equal(t, map[string]int{"Genres": 2, "Favorite": 1, "Playtime": 1}, result.UnmappedFields)
+		assert.Equal(t, map[string]int{"Played": 1}, result.UnmappedStatuses)
+	})
+
+	t.Run("Status mapping", func(t *testing.T) {
+		expected := map[string]playthroughs.PlaythroughStatus{
+			"Playing":   playthroughs.PlaythroughInProgress,
+			"Beaten":    playthroughs.PlaythroughCompleted,
+			"Completed": playthroughs.PlaythroughCompleted,
+			"Abandoned": playthroughs.PlaythroughDropped,
+			"On Hold":   playthroughs.PlaythroughSuspended,
+			"on hold":   playthroughs.PlaythroughSuspended,
+		}
+		for status, mapped := range expected {
+			entries, result := parseString(`[{"Name": "Game", "Platforms": ["PC"], "CompletionStatus": "` + status + `"}]`)
+
+			assert.Empty(t, result.Errors, status)
+			assert.Equal(t, mapped, entries[0].playthrough.Status, status)
+		}
+	})
+
+	t.Run("Not played has no playthrough", func(t *testing.T) {
+		entries, result := parseString(`[{"Name": "Game", "Platforms": ["PC"], "CompletionStatus": "Not Played"}]`)
+
+		assert.Nil(t, entries[0].playthrough)
+		assert.Empty(t, result.UnmappedStatuses)
+	})
+
+	t.Run("Wrapped in object", func(t *testing.T) {
+		entries, result := parseString(`{"games": [{"name": "Game", "platforms": ["PC"]}]}`)
+
+		assert.Empty(t, result.Errors)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "Game", entries[0].game.Title)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, result := parseString(`Hollow Knight`)
+
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, 0, result.Errors[0].Index)
+	})
+
+	t.Run("Invalid games reported", func(t *testing.T) {
+		content := `[
+			{"Platforms": ["PC"]},
+			{"Name": "No platform"},
+			{"Name": "Bad playtime", "Platforms": ["PC"], "Playtime": -5},
+			{"Name": "Bad date", "Platforms": ["PC"], "Added": "yesterday"},
+			{"Name": "Valid", "Platforms": ["PC"]}
+		]`
+
+		entries, result := parseString(content)
+
+		assert.Len(t, entries, 1)
+		assert.Len(t, result.Errors, 4)
+		assert.Equal(t, 1, result.Errors[0].Index)
+		assert.Equal(t, "No platform", result.Errors[1].Title)
+		assert.Equal(t, 3, result.Errors[2].Index)
+		assert.Equal(t, 4, result.Errors[3].Index)
+	})
+}
+
+func TestStartDate(t *testing.T) {
+	added := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	lastActivity := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Earlier of both", func(t *testing.T) {
+		assert.Equal(t, lastActivity, startDate(makeNullTime(added), makeNullTime(lastActivity), testNow))
+	})
+
+	t.Run("Added only", func(t *testing.T) {
+		assert.Equal(t, added, startDate(makeNullTime(added), makeNullTime(time.Time{}), testNow))
+	})
+
+	t.Run("Neither", func(t *testing.T) {
+		assert.Equal(t, testNow, startDate(makeNullTime(time.Time{}), makeNullTime(time.Time{}), testNow))
+	})
+}
+
+func makeNullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Valid: !value.IsZero(), Time: value}
+}
@@ -0,0 +1,31 @@
+package playsessions
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PlaySession is a single sitting within a playthrough.
+// Its length is defined either by the end time or by the duration.
+type PlaySession struct {
+	Id            uuid.UUID
+	PlaythroughId uuid.UUID
+	StartTime     time.Time
+	EndTime       sql.NullTime
+	Duration      sql.NullInt32
+	Note          sql.NullString
+}
+
+func (p *PlaySession) SetId(id uuid.UUID) {
+	p.Id = id
+}
+
+func scanPlaySession(row gotabase.Row) (*PlaySession, error) {
+	var session PlaySession
+	if err := row.Scan(&session.Id, &session.PlaythroughId, &session.StartTime, &session.EndTime, &session.Duration, &session.Note); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,77 @@
+package playsessions
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+)
+
+// GetPlaySessions returns all sessions of the playthrough, starting with the most recent one.
+func GetPlaySessions(playthroughId uuid.UUID, userId uuid.UUID) ([]*PlaySession, error) {
+	query := `select id, playthrough_id, start_time, end_time, duration_minutes, note from play_sessions where playthrough_id = $1 and check_user_playthrough($2, playthrough_id) order by start_time desc`
+	return operations.QueryRows(getDatabase(), scanPlaySession, query, playthroughId, userId)
+}
+
+// GetPlaySession returns a single [PlaySession] of the playthrough.
+func GetPlaySession(id uuid.UUID, playthroughId uuid.UUID, userId uuid.UUID) (*PlaySession, error) {
+	query := `select id, playthrough_id, start_time, end_time, duration_minutes, note from play_sessions where id = $1 and playthrough_id = $2 and check_user_playthrough($3, playthrough_id)`
+	return operations.QueryRow(getDatabase(), scanPlaySession, query, id, playthroughId, userId)
+}
+
+// CreatePlaySession adds a new [PlaySession] to the playthrough.
+func CreatePlaySession(session *PlaySession, userId uuid.UUID) error {
+	query := `insert into play_sessions (playthrough_id, start_time, end_time, duration_minutes, note)
+		select $1::uuid, $2::timestamptz, $3::timestamptz, $4::integer, $5::varchar where check_user_playthrough($6, $1)
+		returning id`
+	return operations.CreateRowWithId(getDatabase(), session, query, session.PlaythroughId, session.StartTime, session.EndTime, session.Duration, session.Note, userId)
+}
+
+// UpdatePlaySession updates details of a single [PlaySession], which creates a new version of its playthrough.
+//
+// If the playthrough version is not 0, the session is only updated if the playthrough still has that version, VersionMismatchErr from utils is returned otherwise.
+func UpdatePlaySession(session *PlaySession, playthroughVersion int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		if err := lockPlaythrough(tx, session.PlaythroughId, playthroughVersion, userId); err != nil {
+			return err
+		}
+
+		query := `update play_sessions set start_time = $3, end_time = $4, duration_minutes = $5, note = $6 where id = $1 and playthrough_id = $2 and check_user_playthrough($7, playthrough_id)`
+		return operations.UpdateRow(tx, query, session.Id, session.PlaythroughId, session.StartTime, session.EndTime, session.Duration, session.Note, userId)
+	})
+}
+
+// DeletePlaySession removes a single [PlaySession] from the playthrough, which creates a new version of the playthrough.
+//
+// If the playthrough version is not 0, the session is only removed if the playthrough still has that version, VersionMismatchErr from utils is returned otherwise.
+func DeletePlaySession(id uuid.UUID, playthroughId uuid.UUID, playthroughVersion int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		if err := lockPlaythrough(tx, playthroughId, playthroughVersion, userId); err != nil {
+			return err
+		}
+
+		query := `delete from play_sessions where id = $1 and playthrough_id = $2 and check_user_playthrough($3, playthrough_id)`
+		return operations.DeleteRow(tx, query, id, playthroughId, userId)
+	})
+}
+
+// lockPlaythrough locks the playthrough of the user until the end of the transaction, so that it cannot change before its sessions are.
+// If the version is not 0, the playthrough must still have that version.
+func lockPlaythrough(tx gotabase.Connector, playthroughId uuid.UUID, version int, userId uuid.UUID) error {
+	query := `select id from playthroughs where id = $1 and check_user_playthrough($2, id) and ($3::integer = 0 or version = $3) for update`
+	if _, err := operations.QueryRow(tx, scanId, query, playthroughId, userId, version); err != nil {
+		return utils.CheckVersionMismatch(err, version, func() bool {
+			_, err := operations.QueryRow(tx, scanId, query, playthroughId, userId, 0)
+			return err == nil
+		})
+	}
+	return nil
+}
+
+func scanId(row gotabase.Row) (*uuid.UUID, error) {
+	var id uuid.UUID
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
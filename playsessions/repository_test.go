@@ -0,0 +1,239 @@
+package playsessions
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makePlaythrough(userId uuid.UUID) uuid.UUID {
+	platformId := tests.GetRandomUuid()
+	_, err := getDatabase().Exec(`insert into platforms (id, name, short_name, user_id) values ($1, 'aa', 'aa', $2)`, platformId, userId)
+	tests.PanicOnErr(err)
+	gameId := tests.GetRandomUuid()
+	_, err = getDatabase().Exec(`insert into games (id, title, platform_id, release_date, released, user_id) values ($1, 'test', $2, null, true, $3)`, gameId, platformId, userId)
+	tests.PanicOnErr(err)
+	id := tests.GetRandomUuid()
+	_, err = getDatabase().Exec(`insert into playthroughs (id, game_id, start_date, status) values ($1, $2, now(), 0)`, id, gameId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makeSession(playthroughId uuid.UUID, startTime time.Time, duration int) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into play_sessions (id, playthrough_id, start_time, duration_minutes) values ($1, $2, $3, $4)`
+	_, err := getDatabase().Exec(query, id, playthroughId, startTime, duration)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func TestGetPlaySessions(t *testing.T) {
+	t.Run("Returns sessions of playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		older := makeSession(playthroughId, time.Now().Add(-48*time.Hour), 30)
+		newer := makeSession(playthroughId, time.Now().Add(-24*time.Hour), 60)
+		makeSession(makePlaythrough(userId), time.Now(), 10)
+
+		sessions, err := GetPlaySessions(playthroughId, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 2)
+		assert.Equal(t, newer, sessions[0].Id)
+		assert.Equal(t, older, sessions[1].Id)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		makeSession(playthroughId, time.Now(), 30)
+
+		sessions, err := GetPlaySessions(playthroughId, tests.MakeTestUserId(getDatabase()))
+
+		assert.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+}
+
+func TestGetPlaySession(t *testing.T) {
+	t.Run("Returns session", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		id := makeSession(playthroughId, time.Now(), 30)
+
+		session, err := GetPlaySession(id, playthroughId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, session.Id)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 30}, session.Duration)
+	})
+
+	t.Run("Session not found", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		_, err := GetPlaySession(tests.GetRandomUuid(), makePlaythrough(userId), userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		id := makeSession(playthroughId, time.Now(), 30)
+
+		_, err := GetPlaySession(id, playthroughId, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestCreatePlaySession(t *testing.T) {
+	t.Run("New session created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := PlaySession{
+			PlaythroughId: makePlaythrough(userId),
+			StartTime:     tests.GetRandomTestTime(),
+			Duration:      sql.NullInt32{Valid: true, Int32: 45},
+			Note:          sql.NullString{Valid: true, String: "boss fight"},
+		}
+
+		err := CreatePlaySession(&session, userId)
+
+		assert.NoError(t, err)
+		dbSession, err := GetPlaySession(session.Id, session.PlaythroughId, userId)
+		tests.PanicOnErr(err)
+		dbSession.StartTime = dbSession.StartTime.UTC()
+		assert.Equal(t, session, *dbSession)
+	})
+
+	t.Run("Neither end time nor duration returns error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := PlaySession{
+			PlaythroughId: makePlaythrough(userId),
+			StartTime:     tests.GetRandomTestTime(),
+		}
+
+		err := CreatePlaySession(&session, userId)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := PlaySession{
+			PlaythroughId: makePlaythrough(userId),
+			StartTime:     tests.GetRandomTestTime(),
+			Duration:      sql.NullInt32{Valid: true, Int32: 45},
+		}
+
+		err := CreatePlaySession(&session, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestUpdatePlaySession(t *testing.T) {
+	t.Run("Updates existing session", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		startTime := tests.GetRandomTestTime()
+		session := PlaySession{
+			Id:            makeSession(playthroughId, time.Now(), 30),
+			PlaythroughId: playthroughId,
+			StartTime:     startTime,
+			EndTime:       sql.NullTime{Valid: true, Time: startTime.Add(90 * time.Minute)},
+		}
+
+		err := UpdatePlaySession(&session, 0, userId)
+
+		assert.NoError(t, err)
+		dbSession, err := GetPlaySession(session.Id, playthroughId, userId)
+		tests.PanicOnErr(err)
+		dbSession.StartTime = dbSession.StartTime.UTC()
+		dbSession.EndTime.Time = dbSession.EndTime.Time.UTC()
+		assert.Equal(t, session, *dbSession)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		session := PlaySession{
+			Id:            makeSession(playthroughId, time.Now(), 30),
+			PlaythroughId: playthroughId,
+			StartTime:     tests.GetRandomTestTime(),
+			Duration:      sql.NullInt32{Valid: true, Int32: 10},
+		}
+
+		err := UpdatePlaySession(&session, 0, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Playthrough version mismatch - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		session := PlaySession{
+			Id:            makeSession(playthroughId, time.Now(), 30),
+			PlaythroughId: playthroughId,
+			StartTime:     tests.GetRandomTestTime(),
+			Duration:      sql.NullInt32{Valid: true, Int32: 10},
+		}
+
+		err := UpdatePlaySession(&session, 1, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+	})
+}
+
+func TestDeletePlaySession(t *testing.T) {
+	t.Run("Deletes existing session", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		id := makeSession(playthroughId, time.Now(), 30)
+
+		err := DeletePlaySession(id, playthroughId, 0, userId)
+
+		assert.NoError(t, err)
+		_, err = GetPlaySession(id, playthroughId, userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		id := makeSession(playthroughId, time.Now(), 30)
+
+		err := DeletePlaySession(id, playthroughId, 0, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Playthrough version mismatch - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId)
+		id := makeSession(playthroughId, time.Now(), 30)
+
+		err := DeletePlaySession(id, playthroughId, 1, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+	})
+}
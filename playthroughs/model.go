@@ -3,7 +3,10 @@ package playthroughs
 import (
 	"database/sql"
 	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +20,43 @@ const (
 	PlaythroughSuspended
 )
 
+var statusNames = map[PlaythroughStatus]string{
+	PlaythroughInProgress: "In progress",
+	PlaythroughCompleted:  "Completed",
+	PlaythroughDropped:    "Dropped",
+	PlaythroughRetired:    "Retired",
+	PlaythroughSuspended:  "Suspended",
+}
+
+// String returns a human-readable name of the status.
+func (s PlaythroughStatus) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return strconv.Itoa(int(s))
+}
+
+// ParsePlaythroughStatus reads a status from either its name, as returned by String, or its numeric value.
+// Names are matched case-insensitively, with underscores and dashes treated as spaces.
+func ParsePlaythroughStatus(value string) (PlaythroughStatus, bool) {
+	normalised := strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(value)))
+	for status, name := range statusNames {
+		if strings.ToLower(name) == normalised {
+			return status, true
+		}
+	}
+
+	number, err := strconv.Atoi(normalised)
+	if err != nil {
+		return 0, false
+	}
+	status := PlaythroughStatus(number)
+	if _, ok := statusNames[status]; !ok {
+		return 0, false
+	}
+	return status, true
+}
+
 type Playthrough struct {
 	Id        uuid.UUID
 	GameId    uuid.UUID
@@ -24,16 +64,44 @@ type Playthrough struct {
 	EndDate   sql.NullTime
 	Status    PlaythroughStatus
 	Runtime   sql.NullInt32
+
+	// SessionCount and LastPlayed summarise the play sessions and are ignored when writing playthroughs.
+	SessionCount int
+	LastPlayed   sql.NullTime
+
+	// Version is increased with every change of the playthrough, including changes of its play sessions.
+	Version int
 }
 
 func (p *Playthrough) SetId(id uuid.UUID) {
 	p.Id = id
 }
 
+func (p *Playthrough) webhookData() *webhooks.PlaythroughData {
+	data := &webhooks.PlaythroughData{
+		Id:        p.Id,
+		GameId:    p.GameId,
+		StartDate: p.StartDate,
+		Status:    int(p.Status),
+	}
+	if p.EndDate.Valid {
+		data.EndDate = &p.EndDate.Time
+	}
+	if p.Runtime.Valid {
+		runtime := int(p.Runtime.Int32)
+		data.Runtime = &runtime
+	}
+	return data
+}
+
 func scanPlaythrough(row gotabase.Row) (*Playthrough, error) {
 	var p Playthrough
-	if err := row.Scan(&p.Id, &p.GameId, &p.StartDate, &p.EndDate, &p.Status, &p.Runtime); err != nil {
+	if err := row.Scan(&p.Id, &p.GameId, &p.StartDate, &p.EndDate, &p.Status, &p.Runtime, &p.SessionCount, &p.LastPlayed, &p.Version); err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
+
+func scanIdAndVersion(row gotabase.Row, playthrough *Playthrough) error {
+	return row.Scan(&playthrough.Id, &playthrough.Version)
+}
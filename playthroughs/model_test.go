@@ -0,0 +1,39 @@
+package playthroughs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePlaythroughStatus(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		expected PlaythroughStatus
+		ok       bool
+	}{
+		"Name":                  {value: "Completed", expected: PlaythroughCompleted, ok: true},
+		"Lowercase name":        {value: "dropped", expected: PlaythroughDropped, ok: true},
+		"Name with underscores": {value: "IN_PROGRESS", expected: PlaythroughInProgress, ok: true},
+		"Name with spaces":      {value: " in progress ", expected: PlaythroughInProgress, ok: true},
+		"Number":                {value: "4", expected: PlaythroughSuspended, ok: true},
+		"Number out of range":   {value: "5", ok: false},
+		"Unknown name":          {value: "beaten", ok: false},
+		"Empty":                 {value: "", ok: false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, ok := ParsePlaythroughStatus(c.value)
+
+			assert.Equal(t, c.ok, ok)
+			if c.ok {
+				assert.Equal(t, c.expected, status)
+			}
+		})
+	}
+}
+
+func TestPlaythroughStatusString(t *testing.T) {
+	assert.Equal(t, "In progress", PlaythroughInProgress.String())
+	assert.Equal(t, "Retired", PlaythroughRetired.String())
+	assert.Equal(t, "9", PlaythroughStatus(9).String())
+}
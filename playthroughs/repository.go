@@ -2,50 +2,156 @@ package playthroughs
 
 import (
 	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 )
 
+// SessionRuntime sums the length of play sessions in minutes, using their duration when set, and the time between their start and end otherwise.
+// It is shared by all queries deriving the runtime of playthroughs from play sessions, so that they never disagree.
+const SessionRuntime = `sum(coalesce(duration_minutes, extract(epoch from end_time - start_time)::integer / 60))::integer`
+
+// selectPlaythroughs selects playthroughs along with the summary of their play sessions.
+// When a playthrough has any sessions, its runtime is the sum of their lengths instead of the stored value.
+const selectPlaythroughs = `select p.id, p.game_id, p.start_date, p.end_date, p.status, coalesce(s.runtime, p.runtime_minutes), s.session_count, s.last_played, p.version
+	from playthroughs p
+	cross join lateral (
+		select ` + SessionRuntime + ` runtime,
+			count(1) session_count,
+			max(coalesce(start_time + make_interval(mins => duration_minutes), end_time)) last_played
+		from play_sessions where playthrough_id = p.id
+	) s`
+
 // GetPlaythroughs returns a list of playthroughs.
 func GetPlaythroughs(gameId uuid.UUID, userId uuid.UUID) ([]*Playthrough, error) {
-	query := `select id, game_id, start_date, end_date, status, runtime_minutes from playthroughs where check_user_playthrough($1, id) %s order by start_date desc`
+	return GetPlaythroughsTx(getDatabase(), gameId, userId)
+}
+
+// GetPlaythroughsTx works like GetPlaythroughs, but uses the provided connector, so that it can be run in a transaction.
+func GetPlaythroughsTx(connector gotabase.Connector, gameId uuid.UUID, userId uuid.UUID) ([]*Playthrough, error) {
+	query := selectPlaythroughs + ` where check_user_playthrough($1, p.id) %s order by p.start_date desc`
 	args := make([]interface{}, 1)
 	args[0] = userId
 
 	if gameId != uuid.Nil {
-		query = fmt.Sprintf(query, "and game_id = $2 %s")
+		query = fmt.Sprintf(query, "and p.game_id = $2 %s")
 		args = append(args, gameId)
 	}
 	query = fmt.Sprintf(query, "")
 
-	return operations.QueryRows(getDatabase(), scanPlaythrough, query, args...)
+	return operations.QueryRows(connector, scanPlaythrough, query, args...)
 }
 
 // GetPlaythrough returns a single playthrough selected by id.
 func GetPlaythrough(id uuid.UUID, userId uuid.UUID) (*Playthrough, error) {
-	query := `select id, game_id, start_date, end_date, status, runtime_minutes from playthroughs where id = $1 and check_user_playthrough($2, id)`
+	query := selectPlaythroughs + ` where p.id = $1 and check_user_playthrough($2, p.id)`
 	return operations.QueryRow(getDatabase(), scanPlaythrough, query, id, userId)
 }
 
 // CreatePlaythrough creates a new playthrough.
 func CreatePlaythrough(playthrough *Playthrough, userId uuid.UUID) error {
-	if !games.IsUserAuthorised(getDatabase(), playthrough.GameId, userId) {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		return CreatePlaythroughTx(tx, playthrough, userId)
+	})
+}
+
+// CreatePlaythroughTx works like CreatePlaythrough, but uses the provided connector, so that it can be run in a transaction.
+// Playthroughs created as completed or dropped queue the matching event for the webhooks of the user.
+func CreatePlaythroughTx(connector gotabase.Connector, playthrough *Playthrough, userId uuid.UUID) error {
+	if !games.IsUserAuthorised(connector, playthrough.GameId, userId) {
 		return operations.Errors.DataNotFoundErr
 	}
 
-	query := `insert into playthroughs (game_id, start_date, end_date, status, runtime_minutes) values ($1, $2, $3, $4, $5) returning id`
-	return operations.CreateRowWithId(getDatabase(), playthrough, query, playthrough.GameId, playthrough.StartDate, playthrough.EndDate, playthrough.Status, playthrough.Runtime)
+	query := `insert into playthroughs (game_id, start_date, end_date, status, runtime_minutes) values ($1, $2, $3, $4, $5) returning id, version`
+	if err := operations.CreateRowWithScan(connector, playthrough, scanIdAndVersion, query, playthrough.GameId, playthrough.StartDate, playthrough.EndDate, playthrough.Status, playthrough.Runtime); err != nil {
+		return err
+	}
+	return emitStatusEvent(connector, playthrough, userId)
 }
 
-// UpdatePlaythrough updates details about a single playthrough in the database.
+// UpdatePlaythrough updates details about a single playthrough in the database, and sets its new version.
+//
+// If the version of the playthrough is set, the playthrough is only updated if it still has that version, VersionMismatchErr from utils is returned otherwise.
 func UpdatePlaythrough(playthrough *Playthrough, userId uuid.UUID) error {
-	query := `update playthroughs set start_date = $2, end_date = $3, status = $4, runtime_minutes = $5 where id = $1 and check_user_playthrough($6, id)`
-	return operations.UpdateRow(getDatabase(), query, playthrough.Id, playthrough.StartDate, playthrough.EndDate, playthrough.Status, playthrough.Runtime, userId)
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		return UpdatePlaythroughTx(tx, playthrough, userId)
+	})
+}
+
+// UpdatePlaythroughTx works like UpdatePlaythrough, but uses the provided connector, so that it can be run in a transaction.
+// Playthroughs changing their status to completed or dropped queue the matching event for the webhooks of the user.
+// The game of the playthrough cannot be changed, and is read back into the playthrough.
+func UpdatePlaythroughTx(connector gotabase.Connector, playthrough *Playthrough, userId uuid.UUID) error {
+	query := `update playthroughs p set start_date = $2, end_date = $3, status = $4, runtime_minutes = $5
+		from playthroughs old
+		where p.id = $1 and old.id = p.id and check_user_playthrough($6, p.id) and ($7::integer = 0 or p.version = $7)
+		returning p.game_id, old.status, p.version`
+	var previous PlaythroughStatus
+	_, err := operations.QueryRow(connector, func(row gotabase.Row) (*Playthrough, error) {
+		return playthrough, row.Scan(&playthrough.GameId, &previous, &playthrough.Version)
+	}, query, playthrough.Id, playthrough.StartDate, playthrough.EndDate, playthrough.Status, playthrough.Runtime, userId, playthrough.Version)
+	if err != nil {
+		return utils.CheckVersionMismatch(err, playthrough.Version, func() bool { return isUserAuthorised(connector, playthrough.Id, userId) })
+	}
+
+	if previous == playthrough.Status {
+		return nil
+	}
+	return emitStatusEvent(connector, playthrough, userId)
+}
+
+// emitStatusEvent queues the event matching the status of the playthrough, if there is one.
+func emitStatusEvent(connector gotabase.Connector, playthrough *Playthrough, userId uuid.UUID) error {
+	var event webhooks.EventType
+	switch playthrough.Status {
+	case PlaythroughCompleted:
+		event = webhooks.EventPlaythroughCompleted
+	case PlaythroughDropped:
+		event = webhooks.EventPlaythroughDropped
+	default:
+		return nil
+	}
+
+	data, err := operations.QueryRow(connector, func(row gotabase.Row) (*webhooks.PlaythroughData, error) {
+		data := playthrough.webhookData()
+		return data, row.Scan(&data.GameTitle)
+	}, `select title from games where id = $1`, playthrough.GameId)
+	if err != nil {
+		return err
+	}
+	return webhooks.EmitTx(connector, event, data, userId)
 }
 
-// DeletePlaythrough deletes a single playthrough from the database
-func DeletePlaythrough(id uuid.UUID, userId uuid.UUID) error {
-	query := `delete from playthroughs where id = $1 and check_user_playthrough($2, id)`
-	return operations.DeleteRow(getDatabase(), query, id, userId)
+// DeletePlaythrough moves a single playthrough to the trash, discarding its timer, if one is running.
+// The playthrough is removed for good once the trash is purged.
+//
+// If the version is not 0, the playthrough is only deleted if it still has that version, VersionMismatchErr from utils is returned otherwise.
+func DeletePlaythrough(id uuid.UUID, version int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		query := `update playthroughs set deleted_at = now() where id = $1 and check_user_playthrough($2, id) and ($3::integer = 0 or version = $3)`
+		if err := operations.UpdateRow(tx, query, id, userId, version); err != nil {
+			return utils.CheckVersionMismatch(err, version, func() bool { return isUserAuthorised(tx, id, userId) })
+		}
+
+		_, err := tx.Exec(`delete from playthrough_timers where playthrough_id = $1`, id)
+		return operations.Errors.HandleError(err)
+	})
+}
+
+func isUserAuthorised(connector gotabase.Connector, id uuid.UUID, userId uuid.UUID) bool {
+	row, err := connector.QueryRow(`select check_user_playthrough($1, $2)`, userId, id)
+	if err != nil {
+		log.Warnf("Failed to check user authorised: %v", err)
+		return false
+	}
+	var authorised bool
+	if err = row.Scan(&authorised); err != nil {
+		log.Warnf("Failed to check user authorised: %v", err)
+		return false
+	}
+	return authorised
 }
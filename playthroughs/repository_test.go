@@ -3,7 +3,10 @@ package playthroughs
 import (
 	"database/sql"
 	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/games"
 	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/KowalskiPiotr98/ludivault/webhooks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -41,7 +44,7 @@ func TestCreatePlaythrough(t *testing.T) {
 		err := CreatePlaythrough(&playthrough, userId)
 
 		assert.NoError(t, err)
-		dbRow, err := getDatabase().QueryRow("select id, game_id, start_date, end_date, status, runtime_minutes from playthroughs where id = $1", playthrough.Id)
+		dbRow, err := getDatabase().QueryRow("select id, game_id, start_date, end_date, status, runtime_minutes, 0, null::timestamptz, version from playthroughs where id = $1", playthrough.Id)
 		tests.PanicOnErr(err)
 		dbPlaythrough, err := scanPlaythrough(dbRow)
 		tests.PanicOnErr(err)
@@ -201,6 +204,32 @@ func TestGetPlaythrough(t *testing.T) {
 		assert.Equal(t, playthrough, *db)
 	})
 
+	t.Run("Runtime aggregated from play sessions", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthrough := Playthrough{
+			GameId:    makeGame("test", makePlatform(userId), userId),
+			StartDate: tests.GetRandomTestTime(),
+			Status:    PlaythroughInProgress,
+			Runtime:   sql.NullInt32{Valid: true, Int32: 5},
+		}
+		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
+		lastStart := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+		_, err := getDatabase().Exec(`insert into play_sessions (playthrough_id, start_time, duration_minutes) values ($1, $2, 30)`, playthrough.Id, lastStart.Add(-24*time.Hour))
+		tests.PanicOnErr(err)
+		_, err = getDatabase().Exec(`insert into play_sessions (playthrough_id, start_time, end_time) values ($1, $2, $3)`, playthrough.Id, lastStart, lastStart.Add(90*time.Minute))
+		tests.PanicOnErr(err)
+
+		db, err := GetPlaythrough(playthrough.Id, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 120}, db.Runtime)
+		assert.Equal(t, 2, db.SessionCount)
+		assert.True(t, db.LastPlayed.Valid)
+		assert.Equal(t, lastStart.Add(90*time.Minute), db.LastPlayed.Time.UTC())
+		assert.Equal(t, 3, db.Version)
+	})
+
 	t.Run("Playthrough not found", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
 
@@ -281,6 +310,55 @@ func TestUpdatePlaythrough(t *testing.T) {
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
+
+	t.Run("Version changed in the meantime - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthrough := Playthrough{
+			GameId:    makeGame("test", makePlatform(userId), userId),
+			StartDate: tests.GetRandomTestTime(),
+			Status:    PlaythroughInProgress,
+		}
+		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
+		other := playthrough
+		other.Status = PlaythroughSuspended
+		tests.PanicOnErr(UpdatePlaythrough(&other, userId))
+		playthrough.Status = PlaythroughCompleted
+
+		err := UpdatePlaythrough(&playthrough, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+		dbPlaythrough, err := GetPlaythrough(playthrough.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, PlaythroughSuspended, dbPlaythrough.Status)
+		assert.Equal(t, 2, dbPlaythrough.Version)
+	})
+}
+
+func TestPlaythroughWebhookEvents(t *testing.T) {
+	t.Run("Event queued only when status changes", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := &webhooks.Webhook{Url: "http://localhost/hook", Secret: "test-secret-0123456789", Events: []webhooks.EventType{webhooks.EventPlaythroughCompleted}}
+		tests.PanicOnErr(webhooks.CreateWebhook(webhook, userId))
+		playthrough := Playthrough{
+			GameId:    makeGame("test", makePlatform(userId), userId),
+			StartDate: tests.GetRandomTestTime(),
+			Status:    PlaythroughInProgress,
+		}
+		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
+
+		playthrough.Status = PlaythroughCompleted
+		tests.PanicOnErr(UpdatePlaythrough(&playthrough, userId))
+		playthrough.Runtime = sql.NullInt32{Valid: true, Int32: 10}
+		tests.PanicOnErr(UpdatePlaythrough(&playthrough, userId))
+
+		deliveries, err := webhooks.GetDeliveries(webhook.Id, 0, 10, userId)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, webhooks.EventPlaythroughCompleted, deliveries[0].Event)
+		assert.Contains(t, string(deliveries[0].Payload), `"gameTitle": "test"`)
+	})
 }
 
 func TestDeletePlaythrough(t *testing.T) {
@@ -296,9 +374,43 @@ func TestDeletePlaythrough(t *testing.T) {
 		}
 		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
 
-		err := DeletePlaythrough(playthrough.Id, userId)
+		err := DeletePlaythrough(playthrough.Id, 0, userId)
+
+		assert.NoError(t, err)
+		_, err = GetPlaythrough(playthrough.Id, userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Running timer discarded", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthrough := Playthrough{GameId: makeGame("test", makePlatform(userId), userId), StartDate: tests.GetRandomTestTime()}
+		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
+		_, err := getDatabase().Exec(`insert into playthrough_timers (user_id, playthrough_id) values ($1, $2)`, userId, playthrough.Id)
+		tests.PanicOnErr(err)
+
+		err = DeletePlaythrough(playthrough.Id, 0, userId)
+
+		assert.NoError(t, err)
+		row, err := getDatabase().QueryRow(`select count(1) from playthrough_timers`)
+		tests.PanicOnErr(err)
+		var count int
+		tests.PanicOnErr(row.Scan(&count))
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Playthroughs of deleted game hidden", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		gameId := makeGame("test", makePlatform(userId), userId)
+		playthrough := Playthrough{GameId: gameId, StartDate: tests.GetRandomTestTime()}
+		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
+		tests.PanicOnErr(games.DeleteGame(gameId, 0, userId))
+
+		list, err := GetPlaythroughs(uuid.Nil, userId)
 
 		assert.NoError(t, err)
+		assert.Empty(t, list)
 		_, err = GetPlaythrough(playthrough.Id, userId)
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -306,7 +418,7 @@ func TestDeletePlaythrough(t *testing.T) {
 	t.Run("Playthrough not found", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
 
-		err := DeletePlaythrough(tests.GetRandomUuid(), tests.MakeTestUserId(getDatabase()))
+		err := DeletePlaythrough(tests.GetRandomUuid(), 0, tests.MakeTestUserId(getDatabase()))
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -323,7 +435,7 @@ func TestDeletePlaythrough(t *testing.T) {
 		}
 		tests.PanicOnErr(CreatePlaythrough(&playthrough, userId))
 
-		err := DeletePlaythrough(playthrough.Id, tests.MakeTestUserId(getDatabase()))
+		err := DeletePlaythrough(playthrough.Id, 0, tests.MakeTestUserId(getDatabase()))
 
 		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
@@ -0,0 +1,41 @@
+package reports
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+//go:embed templates/year.html
+var yearTemplateSource string
+
+var yearTemplate = template.Must(template.New("year").Funcs(template.FuncMap{
+	"runtime": formatRuntime,
+	"date":    formatDate,
+}).Parse(yearTemplateSource))
+
+// RenderYearReport writes the report as a self-contained HTML page.
+func RenderYearReport(w io.Writer, report *YearReport) error {
+	return yearTemplate.Execute(w, report)
+}
+
+func formatRuntime(value any) string {
+	var minutes int
+	switch v := value.(type) {
+	case int:
+		minutes = v
+	case int32:
+		minutes = int(v)
+	}
+
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
+
+func formatDate(value time.Time) string {
+	return value.Format("2 January 2006")
+}
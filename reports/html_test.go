@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRenderYearReport(t *testing.T) {
+	t.Run("Renders report", func(t *testing.T) {
+		report := &YearReport{
+			Year:     2024,
+			Started:  3,
+			Finished: 2,
+			Dropped:  1,
+			LongestPlaythrough: &PlaythroughSummary{
+				Title:        "Persona <5>",
+				PlatformName: "PS4",
+				Runtime:      sql.NullInt32{Valid: true, Int32: 6150},
+			},
+			MostPlayedPlatform: &PlatformSummary{Name: "PC", Minutes: 45, Playthroughs: 2},
+			FirstCompletion: &PlaythroughSummary{
+				Title:        "Celeste",
+				PlatformName: "PC",
+				EndDate:      sql.NullTime{Valid: true, Time: time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)},
+			},
+			Months: []*MonthSummary{{Month: time.January, Started: 7}},
+		}
+		var buffer bytes.Buffer
+
+		err := RenderYearReport(&buffer, report)
+
+		assert.NoError(t, err)
+		html := buffer.String()
+		assert.Contains(t, html, "2024 in review")
+		assert.Contains(t, html, "Persona &lt;5&gt;")
+		assert.Contains(t, html, "102h 30m")
+		assert.Contains(t, html, "45m over 2 playthrough(s)")
+		assert.Contains(t, html, "3 February 2024")
+		assert.Contains(t, html, "January")
+		assert.NotContains(t, html, "Nothing was played")
+	})
+
+	t.Run("Renders empty report", func(t *testing.T) {
+		var buffer bytes.Buffer
+
+		err := RenderYearReport(&buffer, &YearReport{Year: 2020})
+
+		assert.NoError(t, err)
+		assert.Contains(t, buffer.String(), "Nothing was played")
+	})
+}
@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+// YearReport summarises what the user played during a single calendar year.
+// Optional parts of the report are nil if there was no matching data.
+type YearReport struct {
+	Year     int
+	Started  int
+	Finished int
+	Dropped  int
+
+	LongestPlaythrough *PlaythroughSummary
+	MostPlayedPlatform *PlatformSummary
+	FirstCompletion    *PlaythroughSummary
+	LastCompletion     *PlaythroughSummary
+
+	// Months always has an entry for each month of the year, starting with January.
+	Months []*MonthSummary
+}
+
+type PlaythroughSummary struct {
+	PlaythroughId uuid.UUID
+	GameId        uuid.UUID
+	Title         string
+	PlatformName  string
+	StartDate     time.Time
+	EndDate       sql.NullTime
+	Runtime       sql.NullInt32
+}
+
+type PlatformSummary struct {
+	PlatformId   uuid.UUID
+	Name         string
+	Minutes      int
+	Playthroughs int
+}
+
+type MonthSummary struct {
+	Month    time.Month
+	Started  int
+	Finished int
+	Dropped  int
+}
+
+// monthCount is a single row of the monthly breakdown query.
+type monthCount struct {
+	kind  string
+	month int
+	count int
+}
+
+func scanPlaythroughSummary(row gotabase.Row) (*PlaythroughSummary, error) {
+	var summary PlaythroughSummary
+	if err := row.Scan(&summary.PlaythroughId, &summary.GameId, &summary.Title, &summary.PlatformName, &summary.StartDate, &summary.EndDate, &summary.Runtime); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func scanPlatformSummary(row gotabase.Row) (*PlatformSummary, error) {
+	var summary PlatformSummary
+	if err := row.Scan(&summary.PlatformId, &summary.Name, &summary.Minutes, &summary.Playthroughs); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func scanMonthCount(row gotabase.Row) (*monthCount, error) {
+	var count monthCount
+	if err := row.Scan(&count.kind, &count.month, &count.count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+func scanYearTotals(row gotabase.Row) (*YearReport, error) {
+	var report YearReport
+	if err := row.Scan(&report.Started, &report.Finished, &report.Dropped); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
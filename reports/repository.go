@@ -0,0 +1,122 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"time"
+)
+
+// userPlaythroughs joins playthroughs of user $1 with their game, platform and play sessions.
+// The runtime is derived from play sessions when there are any.
+const userPlaythroughs = `from playthroughs p
+	join games g on g.id = p.game_id
+	join platforms pl on pl.id = g.platform_id
+	cross join lateral (
+		select ` + playthroughs.SessionRuntime + ` runtime
+		from play_sessions where playthrough_id = p.id
+	) s
+	where g.user_id = $1 and p.deleted_at is null and g.deleted_at is null`
+
+const selectPlaythroughSummaries = `select p.id, g.id, g.title, pl.name, p.start_date, p.end_date, coalesce(s.runtime, p.runtime_minutes) runtime ` + userPlaythroughs
+
+// overlapsYear limits the playthroughs to the ones that were being played during the [$2, $3) range.
+const overlapsYear = ` and p.start_date < $3 and (p.end_date is null or p.end_date >= $2)`
+
+// GetYearReport builds the report of the given year for the user.
+// The year boundaries are calculated in UTC.
+func GetYearReport(year int, userId uuid.UUID) (*YearReport, error) {
+	connector := getDatabase()
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	report, err := getYearTotals(connector, from, to, userId)
+	if err != nil {
+		return nil, err
+	}
+	report.Year = year
+
+	if report.Months, err = getMonths(connector, from, to, userId); err != nil {
+		return nil, err
+	}
+
+	query := selectPlaythroughSummaries + overlapsYear + ` and coalesce(s.runtime, p.runtime_minutes) is not null order by runtime desc, p.start_date limit 1`
+	if report.LongestPlaythrough, err = queryOptional(connector, scanPlaythroughSummary, query, userId, from, to); err != nil {
+		return nil, err
+	}
+
+	query = selectPlaythroughSummaries + ` and p.status = $4 and p.end_date >= $2 and p.end_date < $3 order by p.end_date %s limit 1`
+	if report.FirstCompletion, err = queryOptional(connector, scanPlaythroughSummary, fmt.Sprintf(query, "asc"), userId, from, to, playthroughs.PlaythroughCompleted); err != nil {
+		return nil, err
+	}
+	if report.LastCompletion, err = queryOptional(connector, scanPlaythroughSummary, fmt.Sprintf(query, "desc"), userId, from, to, playthroughs.PlaythroughCompleted); err != nil {
+		return nil, err
+	}
+
+	query = `select pl.id, pl.name, coalesce(sum(coalesce(s.runtime, p.runtime_minutes)), 0)::integer minutes, count(1) played ` +
+		userPlaythroughs + overlapsYear + `
+		group by pl.id, pl.name
+		order by minutes desc, played desc, pl.name
+		limit 1`
+	if report.MostPlayedPlatform, err = queryOptional(connector, scanPlatformSummary, query, userId, from, to); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func getYearTotals(connector gotabase.Connector, from time.Time, to time.Time, userId uuid.UUID) (*YearReport, error) {
+	query := `select
+			count(1) filter (where p.start_date >= $2 and p.start_date < $3),
+			count(1) filter (where p.status = $4 and p.end_date >= $2 and p.end_date < $3),
+			count(1) filter (where p.status = $5 and p.end_date >= $2 and p.end_date < $3)
+		from playthroughs p
+		join games g on g.id = p.game_id
+		where g.user_id = $1 and p.deleted_at is null and g.deleted_at is null`
+	return operations.QueryRow(connector, scanYearTotals, query, userId, from, to, playthroughs.PlaythroughCompleted, playthroughs.PlaythroughDropped)
+}
+
+func getMonths(connector gotabase.Connector, from time.Time, to time.Time, userId uuid.UUID) ([]*MonthSummary, error) {
+	query := `with p as (select p.* from playthroughs p join games g on g.id = p.game_id where g.user_id = $1 and p.deleted_at is null and g.deleted_at is null)
+		select 'started', extract(month from start_date at time zone 'UTC')::integer, count(1) from p
+			where start_date >= $2 and start_date < $3 group by 2
+		union all
+		select 'finished', extract(month from end_date at time zone 'UTC')::integer, count(1) from p
+			where status = $4 and end_date >= $2 and end_date < $3 group by 2
+		union all
+		select 'dropped', extract(month from end_date at time zone 'UTC')::integer, count(1) from p
+			where status = $5 and end_date >= $2 and end_date < $3 group by 2`
+	counts, err := operations.QueryRows(connector, scanMonthCount, query, userId, from, to, playthroughs.PlaythroughCompleted, playthroughs.PlaythroughDropped)
+	if err != nil {
+		return nil, err
+	}
+
+	months := make([]*MonthSummary, 12)
+	for i := range months {
+		months[i] = &MonthSummary{Month: time.Month(i + 1)}
+	}
+	for _, count := range counts {
+		month := months[count.month-1]
+		switch count.kind {
+		case "started":
+			month.Started = count.count
+		case "finished":
+			month.Finished = count.count
+		case "dropped":
+			month.Dropped = count.count
+		}
+	}
+	return months, nil
+}
+
+// queryOptional works like operations.QueryRow, but returns nil instead of an error if no row was found.
+func queryOptional[T any](connector gotabase.Connector, scanner func(row gotabase.Row) (*T, error), query string, args ...any) (*T, error) {
+	result, err := operations.QueryRow(connector, scanner, query, args...)
+	if errors.Is(err, operations.Errors.DataNotFoundErr) {
+		return nil, nil
+	}
+	return result, err
+}
@@ -0,0 +1,104 @@
+package reports
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makePlatform(name string, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into platforms (id, name, short_name, user_id) values ($1, $2, left($2, 5), $3)`
+	_, err := getDatabase().Exec(query, id, name, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makeGame(title string, platformId uuid.UUID, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into games (id, title, platform_id, release_date, released, user_id) values ($1, $2, $3, null, true, $4)`
+	_, err := getDatabase().Exec(query, id, title, platformId, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makePlaythrough(gameId uuid.UUID, start time.Time, end *time.Time, status playthroughs.PlaythroughStatus, runtime int) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into playthroughs (id, game_id, start_date, end_date, status, runtime_minutes) values ($1, $2, $3, $4, $5, $6)`
+	_, err := getDatabase().Exec(query, id, gameId, start, end, status, runtime)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	value := date(year, month, day)
+	return &value
+}
+
+func TestGetYearReport(t *testing.T) {
+	t.Run("Empty year", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		report, err := GetYearReport(2024, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2024, report.Year)
+		assert.Zero(t, report.Started)
+		assert.Nil(t, report.LongestPlaythrough)
+		assert.Nil(t, report.MostPlayedPlatform)
+		assert.Nil(t, report.FirstCompletion)
+		assert.Nil(t, report.LastCompletion)
+		assert.Len(t, report.Months, 12)
+	})
+
+	t.Run("Builds report from playthroughs", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		pcId := makePlatform("PC", userId)
+		switchId := makePlatform("Switch", userId)
+		makePlaythrough(makeGame("Celeste", pcId, userId), date(2024, 1, 5), datePtr(2024, 2, 3), playthroughs.PlaythroughCompleted, 600)
+		makePlaythrough(makeGame("Hades", pcId, userId), date(2023, 12, 1), datePtr(2024, 11, 20), playthroughs.PlaythroughCompleted, 1500)
+		makePlaythrough(makeGame("Zelda", switchId, userId), date(2024, 3, 1), datePtr(2024, 3, 10), playthroughs.PlaythroughDropped, 900)
+		makePlaythrough(makeGame("Metroid", switchId, userId), date(2024, 12, 30), nil, playthroughs.PlaythroughInProgress, 60)
+		makePlaythrough(makeGame("Old", pcId, userId), date(2022, 1, 1), datePtr(2022, 5, 1), playthroughs.PlaythroughCompleted, 5000)
+
+		report, err := GetYearReport(2024, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Started)
+		assert.Equal(t, 2, report.Finished)
+		assert.Equal(t, 1, report.Dropped)
+		assert.Equal(t, "Hades", report.LongestPlaythrough.Title)
+		assert.Equal(t, int32(1500), report.LongestPlaythrough.Runtime.Int32)
+		assert.Equal(t, &PlatformSummary{PlatformId: pcId, Name: "PC", Minutes: 2100, Playthroughs: 2}, report.MostPlayedPlatform)
+		assert.Equal(t, "Celeste", report.FirstCompletion.Title)
+		assert.Equal(t, "Hades", report.LastCompletion.Title)
+		assert.Equal(t, &MonthSummary{Month: time.January, Started: 1}, report.Months[0])
+		assert.Equal(t, &MonthSummary{Month: time.February, Finished: 1}, report.Months[1])
+		assert.Equal(t, &MonthSummary{Month: time.March, Started: 1, Dropped: 1}, report.Months[2])
+		assert.Equal(t, &MonthSummary{Month: time.November, Finished: 1}, report.Months[10])
+		assert.Equal(t, &MonthSummary{Month: time.December, Started: 1}, report.Months[11])
+	})
+
+	t.Run("Other users data not included", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		makePlaythrough(makeGame("Celeste", makePlatform("PC", otherUserId), otherUserId), date(2024, 1, 5), datePtr(2024, 2, 3), playthroughs.PlaythroughCompleted, 600)
+
+		report, err := GetYearReport(2024, userId)
+
+		assert.NoError(t, err)
+		assert.Zero(t, report.Started)
+		assert.Zero(t, report.Finished)
+		assert.Nil(t, report.FirstCompletion)
+	})
+}
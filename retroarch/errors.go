@@ -0,0 +1,7 @@
+package retroarch
+
+import "errors"
+
+var (
+	NoPlaylistsErr = errors.New("at least one playlist is required")
+)
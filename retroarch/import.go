@@ -0,0 +1,167 @@
+package retroarch
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"time"
+)
+
+// gameKey identifies a game by its platform and normalised title.
+type gameKey struct {
+	platformId uuid.UUID
+	title      string
+}
+
+// importedGame collects all entries of the playlists, that refer to the same game.
+type importedGame struct {
+	title      string
+	runtime    time.Duration
+	lastPlayed time.Time
+	logged     bool
+	contents   map[string]bool
+}
+
+// Import creates platforms, games and playthroughs from RetroArch playlists and runtime logs in a single transaction.
+//
+// Each playlist is mapped to a platform with the same name, or short name, which is created if missing.
+// Each entry is mapped to a game on that platform by its title, with tags such as regions removed,
+// so entries for different discs or regions of the same game result in a single game.
+//
+// Runtime logs are matched with entries by the content file name, and their runtimes are summed for each game.
+// If the game has no playthroughs, a new one is created, starting at the last played time.
+// Otherwise, the runtime of its latest playthrough is replaced with the logged one,
+// so running the import again updates runtimes instead of duplicating games and playthroughs.
+//
+// In dry-run mode all changes are made and then rolled back, so that the result shows what would be changed.
+func Import(playlists []*Playlist, logs []*RuntimeLog, dryRun bool, userId uuid.UUID) (*ImportResult, error) {
+	if len(playlists) == 0 {
+		return nil, NoPlaylistsErr
+	}
+
+	existing, err := games.GetAllGames(userId)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[gameKey]*games.Game, len(existing))
+	for _, game := range existing {
+		known[gameKey{platformId: game.PlatformId, title: games.NormaliseTitle(game.Title)}] = game
+	}
+
+	result := &ImportResult{DryRun: dryRun, PlatformsCreated: make([]string, 0), UnmatchedLogs: make([]string, 0)}
+	err = utils.RunInTransactionWithDryRun(dryRun, func(tx gotabase.Connector) error {
+		userPlatforms, err := platforms.GetPlatformsTx(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		matchedLogs := make(map[string]bool, len(logs))
+		for _, playlist := range playlists {
+			platform := platforms.FindByName(userPlatforms, playlist.Name)
+			if platform == nil {
+				if platform, err = platforms.CreateByNameTx(tx, userPlatforms, playlist.Name, userId); err != nil {
+					return err
+				}
+				userPlatforms = append(userPlatforms, platform)
+				result.PlatformsCreated = append(result.PlatformsCreated, platform.Name)
+			}
+
+			for _, imported := range groupEntries(playlist, logs, matchedLogs) {
+				if err = importGame(tx, imported, platform.Id, known, userId, result); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, runtimeLog := range logs {
+			if !matchedLogs[runtimeLog.Content] {
+				result.UnmatchedLogs = append(result.UnmatchedLogs, runtimeLog.Content)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// groupEntries merges the entries of the playlist with the same title, together with their runtime logs.
+// The order of the entries is kept.
+func groupEntries(playlist *Playlist, logs []*RuntimeLog, matchedLogs map[string]bool) []*importedGame {
+	grouped := make([]*importedGame, 0, len(playlist.Entries))
+	byTitle := make(map[string]*importedGame, len(playlist.Entries))
+	for _, entry := range playlist.Entries {
+		title := entry.Title()
+		imported := byTitle[games.NormaliseTitle(title)]
+		if imported == nil {
+			imported = &importedGame{title: title, contents: make(map[string]bool)}
+			byTitle[games.NormaliseTitle(title)] = imported
+			grouped = append(grouped, imported)
+		}
+
+		content := entry.Content()
+		if imported.contents[content] {
+			continue
+		}
+		imported.contents[content] = true
+		for _, runtimeLog := range logs {
+			if runtimeLog.Content != content {
+				continue
+			}
+			matchedLogs[runtimeLog.Content] = true
+			imported.logged = true
+			imported.runtime += runtimeLog.Runtime
+			if runtimeLog.LastPlayed.After(imported.lastPlayed) {
+				imported.lastPlayed = runtimeLog.LastPlayed
+			}
+		}
+	}
+	return grouped
+}
+
+func importGame(tx gotabase.Connector, imported *importedGame, platformId uuid.UUID, known map[gameKey]*games.Game, userId uuid.UUID, result *ImportResult) error {
+	key := gameKey{platformId: platformId, title: games.NormaliseTitle(imported.title)}
+	game := known[key]
+	if game == nil {
+		game = &games.Game{PlatformId: platformId, Title: imported.title, Owned: true, Released: true}
+		if err := games.CreateGameTx(tx, game, userId); err != nil {
+			return err
+		}
+		known[key] = game
+		result.GamesCreated++
+	} else {
+		result.GamesSkipped++
+	}
+
+	if !imported.logged {
+		return nil
+	}
+
+	runtime := sql.NullInt32{Valid: true, Int32: int32(imported.runtime / time.Minute)}
+	gamePlaythroughs, err := playthroughs.GetPlaythroughsTx(tx, game.Id, userId)
+	if err != nil {
+		return err
+	}
+	if len(gamePlaythroughs) > 0 {
+		latest := gamePlaythroughs[0]
+		latest.Runtime = runtime
+		result.PlaythroughsUpdated++
+		return playthroughs.UpdatePlaythroughTx(tx, latest, userId)
+	}
+
+	playthrough := &playthroughs.Playthrough{
+		GameId:    game.Id,
+		StartDate: imported.lastPlayed,
+		Status:    playthroughs.PlaythroughInProgress,
+		Runtime:   runtime,
+	}
+	result.PlaythroughsCreated++
+	return playthroughs.CreatePlaythroughTx(tx, playthrough, userId)
+}
@@ -0,0 +1,125 @@
+package retroarch
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makeTestPlaylist() *Playlist {
+	return &Playlist{
+		Name: "Nintendo - Super Nintendo Entertainment System",
+		Entries: []*Entry{
+			{Path: "/roms/Super Metroid (Japan, USA) (En,Ja).sfc", Label: "Super Metroid (Japan, USA) (En,Ja)"},
+			{Path: "/roms/Chrono Trigger (USA).zip#Chrono Trigger (USA).sfc", Label: "Chrono Trigger (USA)"},
+			{Path: "/roms/Chrono Trigger (Europe).sfc", Label: "Chrono Trigger (Europe)"},
+			{Path: "/roms/homebrew.sfc"},
+		},
+	}
+}
+
+func makeTestLogs() []*RuntimeLog {
+	return []*RuntimeLog{
+		{Content: "Chrono Trigger (USA)", Runtime: time.Hour, LastPlayed: time.Date(2023, 12, 24, 10, 0, 0, 0, time.UTC)},
+		{Content: "Chrono Trigger (Europe)", Runtime: 30 * time.Minute, LastPlayed: time.Date(2024, 1, 10, 18, 0, 0, 0, time.UTC)},
+		{Content: "Unknown Game", Runtime: 5 * time.Minute, LastPlayed: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Run("Platforms, games and playthroughs created", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := Import([]*Playlist{makeTestPlaylist()}, makeTestLogs(), false, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Nintendo - Super Nintendo Entertainment System"}, result.PlatformsCreated)
+		assert.Equal(t, 3, result.GamesCreated)
+		assert.Equal(t, 1, result.PlaythroughsCreated)
+		assert.Equal(t, []string{"Unknown Game"}, result.UnmatchedLogs)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 3)
+		assert.Equal(t, "Chrono Trigger", userGames[0].Title)
+		assert.True(t, userGames[0].Owned)
+		gamePlaythroughs, err := playthroughs.GetPlaythroughs(userGames[0].Id, userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, gamePlaythroughs, 1)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 90}, gamePlaythroughs[0].Runtime)
+		assert.Equal(t, playthroughs.PlaythroughInProgress, gamePlaythroughs[0].Status)
+	})
+
+	t.Run("Existing platform reused", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := &platforms.Platform{Name: "Super Nintendo", ShortName: "SNES"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+		playlist := makeTestPlaylist()
+		playlist.Name = "snes"
+
+		result, err := Import([]*Playlist{playlist}, nil, false, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.PlatformsCreated)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, platform.Id, userGames[0].PlatformId)
+	})
+
+	t.Run("Import can be repeated", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		_, err := Import([]*Playlist{makeTestPlaylist()}, makeTestLogs(), false, userId)
+		tests.PanicOnErr(err)
+		logs := makeTestLogs()
+		logs[0].Runtime = 2 * time.Hour
+
+		result, err := Import([]*Playlist{makeTestPlaylist()}, logs, false, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.PlatformsCreated)
+		assert.Zero(t, result.GamesCreated)
+		assert.Equal(t, 3, result.GamesSkipped)
+		assert.Zero(t, result.PlaythroughsCreated)
+		assert.Equal(t, 1, result.PlaythroughsUpdated)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 3)
+		gamePlaythroughs, err := playthroughs.GetPlaythroughs(userGames[0].Id, userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, gamePlaythroughs, 1)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 150}, gamePlaythroughs[0].Runtime)
+	})
+
+	t.Run("Dry run creates nothing", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		result, err := Import([]*Playlist{makeTestPlaylist()}, makeTestLogs(), true, userId)
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 3, result.GamesCreated)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+		userPlatforms, err := platforms.GetPlatforms(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userPlatforms)
+	})
+
+	t.Run("No playlists", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+
+		_, err := Import(nil, makeTestLogs(), false, userId)
+
+		assert.ErrorIs(t, err, NoPlaylistsErr)
+	})
+}
@@ -0,0 +1,36 @@
+package retroarch
+
+import "time"
+
+// Playlist is a single RetroArch playlist, usually holding the content of one system.
+type Playlist struct {
+	// Name is the file name of the playlist without the extension, such as "Nintendo - Game Boy".
+	Name    string
+	Entries []*Entry
+}
+
+// Entry is a single item of a [Playlist].
+type Entry struct {
+	Path  string
+	Label string
+}
+
+// RuntimeLog holds the playtime of a single content file, as logged by RetroArch.
+type RuntimeLog struct {
+	// Content is the file name of the log without the extension, matching the file name of the content without the extension.
+	Content    string
+	Runtime    time.Duration
+	LastPlayed time.Time
+}
+
+// ImportResult summarises the changes made, or that would be made in dry-run mode, by Import.
+type ImportResult struct {
+	DryRun              bool
+	PlatformsCreated    []string
+	GamesCreated        int
+	GamesSkipped        int
+	PlaythroughsCreated int
+	PlaythroughsUpdated int
+	// UnmatchedLogs lists runtime logs, for which no entry was found in any of the playlists.
+	UnmatchedLogs []string
+}
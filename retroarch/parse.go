@@ -0,0 +1,123 @@
+package retroarch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lastPlayedFormat = "2006-01-02 15:04:05"
+
+// tagsPattern matches region, language and dump tags, which No-Intro and Redump names end with, such as " (USA) (En,Ja) [!]".
+var tagsPattern = regexp.MustCompile(`(\s*[(\[][^()\[\]]*[)\]])+\s*$`)
+
+// ParsePlaylist reads a playlist in the JSON format used by RetroArch since version 1.7.6.
+// The name of the playlist is taken from the file name.
+func ParsePlaylist(fileName string, reader io.Reader) (*Playlist, error) {
+	var content struct {
+		Items []struct {
+			Path  string `json:"path"`
+			Label string `json:"label"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(reader).Decode(&content); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist %s: %w", fileName, err)
+	}
+
+	playlist := &Playlist{
+		Name:    trimExtension(fileName),
+		Entries: make([]*Entry, 0, len(content.Items)),
+	}
+	if playlist.Name == "" {
+		return nil, fmt.Errorf("playlist file name %q is not valid", fileName)
+	}
+	for _, item := range content.Items {
+		if item.Path == "" && item.Label == "" {
+			continue
+		}
+		playlist.Entries = append(playlist.Entries, &Entry{Path: item.Path, Label: item.Label})
+	}
+	return playlist, nil
+}
+
+// ParseRuntimeLog reads a runtime log, written by RetroArch for each played content file.
+// The content is identified by the file name of the log.
+func ParseRuntimeLog(fileName string, reader io.Reader) (*RuntimeLog, error) {
+	var content struct {
+		Runtime    string `json:"runtime"`
+		LastPlayed string `json:"last_played"`
+	}
+	if err := json.NewDecoder(reader).Decode(&content); err != nil {
+		return nil, fmt.Errorf("failed to parse runtime log %s: %w", fileName, err)
+	}
+
+	runtime, err := parseRuntime(content.Runtime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse runtime log %s: %w", fileName, err)
+	}
+	// RetroArch logs the local time without the zone, so it can only be assumed to be UTC
+	lastPlayed, err := time.Parse(lastPlayedFormat, content.LastPlayed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse runtime log %s: last played time %q is not valid", fileName, content.LastPlayed)
+	}
+
+	return &RuntimeLog{
+		Content:    trimExtension(fileName),
+		Runtime:    runtime,
+		LastPlayed: lastPlayed,
+	}, nil
+}
+
+// Title returns the title of the game, based on the label of the entry, or its file name if the label is not set.
+// Tags that follow the title are removed.
+func (e *Entry) Title() string {
+	title := e.Label
+	if title == "" {
+		title = trimExtension(e.Path)
+	}
+	if stripped := strings.TrimSpace(tagsPattern.ReplaceAllString(title, "")); stripped != "" {
+		return stripped
+	}
+	return strings.TrimSpace(title)
+}
+
+// Content returns the file name of the content without the extension, which is used to match the entry with its [RuntimeLog].
+// Archive entries, such as "game.zip#game.sfc", are identified by the archive name.
+func (e *Entry) Content() string {
+	contentPath, _, _ := strings.Cut(e.Path, "#")
+	return trimExtension(contentPath)
+}
+
+// parseRuntime reads the runtime in the H:MM:SS format.
+func parseRuntime(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("runtime %q is not valid", value)
+	}
+
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return 0, fmt.Errorf("runtime %q is not valid", value)
+		}
+		total += time.Duration(number) * units[i]
+	}
+	return total, nil
+}
+
+// trimExtension returns the file name from the path without the extension.
+// Both slashes and backslashes are treated as separators, as the files may come from Windows.
+func trimExtension(filePath string) string {
+	name := path.Base(strings.ReplaceAll(filePath, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -0,0 +1,101 @@
+package retroarch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePlaylist(t *testing.T) {
+	t.Run("Reads fixture", func(t *testing.T) {
+		file, err := os.Open("testdata/Nintendo - Super Nintendo Entertainment System.lpl")
+		assert.NoError(t, err)
+		defer file.Close()
+
+		playlist, err := ParsePlaylist("Nintendo - Super Nintendo Entertainment System.lpl", file)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Nintendo - Super Nintendo Entertainment System", playlist.Name)
+		assert.Len(t, playlist.Entries, 4)
+		assert.Equal(t, "Super Metroid (Japan, USA) (En,Ja)", playlist.Entries[0].Label)
+	})
+
+	t.Run("Empty items skipped", func(t *testing.T) {
+		playlist, err := ParsePlaylist("Sega - Mega Drive.lpl", strings.NewReader(`{"items": [{"path": "", "label": ""}]}`))
+
+		assert.NoError(t, err)
+		assert.Empty(t, playlist.Entries)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := ParsePlaylist("Sega - Mega Drive.lpl", strings.NewReader(`Sega - Mega Drive`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid file name", func(t *testing.T) {
+		_, err := ParsePlaylist("/", strings.NewReader(`{"items": []}`))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRuntimeLog(t *testing.T) {
+	t.Run("Reads fixture", func(t *testing.T) {
+		file, err := os.Open("testdata/Super Metroid (Japan, USA) (En,Ja).lrtl")
+		assert.NoError(t, err)
+		defer file.Close()
+
+		runtimeLog, err := ParseRuntimeLog("Super Metroid (Japan, USA) (En,Ja).lrtl", file)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &RuntimeLog{
+			Content:    "Super Metroid (Japan, USA) (En,Ja)",
+			Runtime:    12*time.Hour + 34*time.Minute + 56*time.Second,
+			LastPlayed: time.Date(2024, 3, 2, 21, 15, 0, 0, time.UTC),
+		}, runtimeLog)
+	})
+
+	t.Run("Invalid runtime", func(t *testing.T) {
+		_, err := ParseRuntimeLog("Game.lrtl", strings.NewReader(`{"runtime": "12:34", "last_played": "2024-03-02 21:15:00"}`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Negative runtime", func(t *testing.T) {
+		_, err := ParseRuntimeLog("Game.lrtl", strings.NewReader(`{"runtime": "-1:00:00", "last_played": "2024-03-02 21:15:00"}`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid last played", func(t *testing.T) {
+		_, err := ParseRuntimeLog("Game.lrtl", strings.NewReader(`{"runtime": "1:00:00", "last_played": "yesterday"}`))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   Entry
+		title   string
+		content string
+	}{
+		{"Tags removed", Entry{Path: "/roms/Super Metroid (Japan, USA) (En,Ja).sfc", Label: "Super Metroid (Japan, USA) (En,Ja)"}, "Super Metroid", "Super Metroid (Japan, USA) (En,Ja)"},
+		{"Dump tags removed", Entry{Path: "/roms/Chrono Trigger.sfc", Label: "Chrono Trigger (Europe) [!]"}, "Chrono Trigger", "Chrono Trigger"},
+		{"Archive content", Entry{Path: "/roms/Chrono Trigger (USA).zip#Chrono Trigger (USA).sfc", Label: "Chrono Trigger (USA)"}, "Chrono Trigger", "Chrono Trigger (USA)"},
+		{"Windows path", Entry{Path: `C:\roms\Chrono Trigger (Europe).sfc`, Label: "Chrono Trigger"}, "Chrono Trigger", "Chrono Trigger (Europe)"},
+		{"Title from path", Entry{Path: "/roms/homebrew.sfc"}, "homebrew", "homebrew"},
+		{"Only tags kept", Entry{Path: "/roms/(Demo).sfc", Label: "(Demo)"}, "(Demo)", "(Demo)"},
+		{"Parentheses inside title kept", Entry{Path: "/roms/a.sfc", Label: "Game (Part 1) Deluxe"}, "Game (Part 1) Deluxe", "a"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.title, test.entry.Title())
+			assert.Equal(t, test.content, test.entry.Content())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package sessions
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	maxIpAddressLength = 45
+	maxUserAgentLength = 500
+)
+
+type Session struct {
+	Id         uuid.UUID
+	UserId     uuid.UUID
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	IpAddress  string
+	UserAgent  string
+}
+
+func (s *Session) SetId(id uuid.UUID) {
+	s.Id = id
+}
+
+// Refresh marks the [Session] as seen just now from the client described by the values provided.
+func (s *Session) Refresh(ipAddress string, userAgent string) {
+	s.LastSeenAt = time.Now()
+	s.IpAddress = truncate(ipAddress, maxIpAddressLength)
+	s.UserAgent = truncate(userAgent, maxUserAgentLength)
+}
+
+// NewSession prepares a new [Session] for the user that will expire after the maxAge passes.
+// Client data that would not fit in the database is truncated.
+func NewSession(userId uuid.UUID, maxAge time.Duration, ipAddress string, userAgent string) *Session {
+	now := time.Now()
+	return &Session{
+		UserId:     userId,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(maxAge),
+		IpAddress:  truncate(ipAddress, maxIpAddressLength),
+		UserAgent:  truncate(userAgent, maxUserAgentLength),
+	}
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
+
+func scanSession(row gotabase.Row) (*Session, error) {
+	var session Session
+	if err := row.Scan(&session.Id, &session.UserId, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.IpAddress, &session.UserAgent); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,42 @@
+package sessions
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/google/uuid"
+)
+
+// CreateSession stores a new [Session] in the database and sets its id in the provided struct.
+func CreateSession(session *Session) error {
+	query := `insert into sessions (user_id, created_at, last_seen_at, expires_at, ip_address, user_agent) values ($1, $2, $3, $4, $5, $6) returning id`
+	return operations.CreateRowWithId(getDatabase(), session, query, session.UserId, session.CreatedAt, session.LastSeenAt, session.ExpiresAt, session.IpAddress, session.UserAgent)
+}
+
+// GetActiveSession returns a single [Session] by id, as long as it was not revoked and has not yet expired.
+func GetActiveSession(id uuid.UUID) (*Session, error) {
+	query := `select id, user_id, created_at, last_seen_at, expires_at, ip_address, user_agent from sessions where id = $1 and expires_at > now()`
+	return operations.QueryRow(getDatabase(), scanSession, query, id)
+}
+
+// GetActiveSessions returns all sessions of the user that were not revoked and have not yet expired.
+func GetActiveSessions(userId uuid.UUID) ([]*Session, error) {
+	query := `select id, user_id, created_at, last_seen_at, expires_at, ip_address, user_agent from sessions where user_id = $1 and expires_at > now() order by last_seen_at desc`
+	return operations.QueryRows(getDatabase(), scanSession, query, userId)
+}
+
+// TouchSession updates the last seen time and client data of the [Session].
+func TouchSession(session *Session) error {
+	query := `update sessions set last_seen_at = $2, ip_address = $3, user_agent = $4 where id = $1`
+	return operations.UpdateRow(getDatabase(), query, session.Id, session.LastSeenAt, session.IpAddress, session.UserAgent)
+}
+
+// RevokeSession removes a single [Session] of the user, which will prevent it from being used again.
+func RevokeSession(id uuid.UUID, userId uuid.UUID) error {
+	query := `delete from sessions where id = $1 and user_id = $2`
+	return operations.DeleteRow(getDatabase(), query, id, userId)
+}
+
+// RevokeOtherSessions removes all sessions of the user, except for the one with the id provided.
+func RevokeOtherSessions(currentId uuid.UUID, userId uuid.UUID) error {
+	query := `delete from sessions where id <> $1 and user_id = $2`
+	return operations.TryDelete(getDatabase(), query, currentId, userId)
+}
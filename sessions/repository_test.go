@@ -0,0 +1,158 @@
+package sessions
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeTestSession(userId uuid.UUID, maxAge time.Duration) *Session {
+	session := NewSession(userId, maxAge, "127.0.0.1", "test agent")
+	tests.PanicOnErr(CreateSession(session))
+	return session
+}
+
+func TestNewSession(t *testing.T) {
+	t.Run("Client data truncated", func(t *testing.T) {
+		session := NewSession(tests.GetRandomUuid(), time.Hour, "127.0.0.1", strings.Repeat("a", maxUserAgentLength+10))
+
+		assert.Len(t, session.UserAgent, maxUserAgentLength)
+		assert.Equal(t, "127.0.0.1", session.IpAddress)
+	})
+}
+
+func TestCreateSession(t *testing.T) {
+	t.Run("New session created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := NewSession(userId, time.Hour, "127.0.0.1", "test agent")
+
+		err := CreateSession(session)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, session.Id)
+	})
+
+	t.Run("Missing user", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		session := NewSession(tests.GetRandomUuid(), time.Hour, "127.0.0.1", "test agent")
+
+		err := CreateSession(session)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestGetActiveSession(t *testing.T) {
+	t.Run("Session active - returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := makeTestSession(userId, time.Hour)
+
+		dbSession, err := GetActiveSession(session.Id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, session.Id, dbSession.Id)
+		assert.Equal(t, userId, dbSession.UserId)
+	})
+
+	t.Run("Session expired - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		session := makeTestSession(tests.MakeTestUserId(getDatabase()), -time.Hour)
+
+		_, err := GetActiveSession(session.Id)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Session revoked - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := makeTestSession(userId, time.Hour)
+		tests.PanicOnErr(RevokeSession(session.Id, userId))
+
+		_, err := GetActiveSession(session.Id)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestGetActiveSessions(t *testing.T) {
+	t.Run("Only active sessions of the user returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		active := makeTestSession(userId, time.Hour)
+		makeTestSession(userId, -time.Hour)
+		makeTestSession(tests.MakeTestUserId(getDatabase()), time.Hour)
+
+		list, err := GetActiveSessions(userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, active.Id, list[0].Id)
+	})
+}
+
+func TestTouchSession(t *testing.T) {
+	t.Run("Client data updated", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		session := makeTestSession(tests.MakeTestUserId(getDatabase()), time.Hour)
+		session.Refresh("10.0.0.1", "other agent")
+
+		err := TouchSession(session)
+
+		assert.NoError(t, err)
+		dbSession, err := GetActiveSession(session.Id)
+		tests.PanicOnErr(err)
+		assert.Equal(t, "10.0.0.1", dbSession.IpAddress)
+		assert.Equal(t, "other agent", dbSession.UserAgent)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Run("Session exists - revoked", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		session := makeTestSession(userId, time.Hour)
+
+		err := RevokeSession(session.Id, userId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("User not authorised", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		session := makeTestSession(tests.MakeTestUserId(getDatabase()), time.Hour)
+
+		err := RevokeSession(session.Id, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		_, err = GetActiveSession(session.Id)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	t.Run("Only current session kept", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		current := makeTestSession(userId, time.Hour)
+		makeTestSession(userId, time.Hour)
+		makeTestSession(userId, time.Hour)
+		otherUserSession := makeTestSession(tests.MakeTestUserId(getDatabase()), time.Hour)
+
+		err := RevokeOtherSessions(current.Id, userId)
+
+		assert.NoError(t, err)
+		list, err := GetActiveSessions(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, current.Id, list[0].Id)
+		_, err = GetActiveSession(otherUserSession.Id)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Stats is an aggregated summary of the user's library over a date range.
+type Stats struct {
+	Statuses            []*StatusCount
+	CompletionsPerMonth []*MonthCount
+	RuntimePerPlatform  []*PlatformRuntime
+	// AverageDaysToComplete is not set if no playthrough was completed in the range.
+	AverageDaysToComplete sql.NullFloat64
+	Backlog               *Backlog
+}
+
+type StatusCount struct {
+	Status playthroughs.PlaythroughStatus
+	Count  int
+}
+
+type MonthCount struct {
+	Month time.Time
+	Count int
+}
+
+type PlatformRuntime struct {
+	PlatformId uuid.UUID
+	Name       string
+	Minutes    int
+}
+
+// Backlog counts the games that were not yet finished, split by whether they are owned.
+type Backlog struct {
+	Owned   int
+	Unowned int
+}
+
+func scanStatusCount(row gotabase.Row) (*StatusCount, error) {
+	var count StatusCount
+	if err := row.Scan(&count.Status, &count.Count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+func scanMonthCount(row gotabase.Row) (*MonthCount, error) {
+	var count MonthCount
+	if err := row.Scan(&count.Month, &count.Count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+func scanPlatformRuntime(row gotabase.Row) (*PlatformRuntime, error) {
+	var runtime PlatformRuntime
+	if err := row.Scan(&runtime.PlatformId, &runtime.Name, &runtime.Minutes); err != nil {
+		return nil, err
+	}
+	return &runtime, nil
+}
+
+func scanBacklog(row gotabase.Row) (*Backlog, error) {
+	var backlog Backlog
+	if err := row.Scan(&backlog.Owned, &backlog.Unowned); err != nil {
+		return nil, err
+	}
+	return &backlog, nil
+}
+
+func scanNullFloat(row gotabase.Row) (*sql.NullFloat64, error) {
+	var value sql.NullFloat64
+	if err := row.Scan(&value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
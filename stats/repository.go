@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+)
+
+// userPlaythroughs limits the playthroughs to the ones of user $1, that overlap with the [$2, $3) range.
+// Either end of the range can be null, leaving it unbounded.
+const userPlaythroughs = `from playthroughs p
+	join games g on g.id = p.game_id
+	where g.user_id = $1 and p.deleted_at is null and g.deleted_at is null
+	and ($3::timestamptz is null or p.start_date < $3)
+	and ($2::timestamptz is null or p.end_date is null or p.end_date >= $2)`
+
+// userCompletions limits the playthroughs to the ones of user $1, that were completed in the [$2, $3) range.
+const userCompletions = `from playthroughs p
+	join games g on g.id = p.game_id
+	where g.user_id = $1 and p.deleted_at is null and g.deleted_at is null and p.status = $4 and p.end_date is not null
+	and ($2::timestamptz is null or p.end_date >= $2)
+	and ($3::timestamptz is null or p.end_date < $3)`
+
+// GetStats computes the statistics of the user's library for the [from, to) range.
+//
+// Playthroughs are counted if they overlap with the range, while completions are counted by their end date.
+// The backlog always reflects the current state of the library.
+func GetStats(from sql.NullTime, to sql.NullTime, userId uuid.UUID) (*Stats, error) {
+	connector := getDatabase()
+	var stats Stats
+	var err error
+
+	if stats.Statuses, err = getStatusCounts(connector, from, to, userId); err != nil {
+		return nil, err
+	}
+	if stats.CompletionsPerMonth, err = getCompletionsPerMonth(connector, from, to, userId); err != nil {
+		return nil, err
+	}
+	if stats.RuntimePerPlatform, err = getRuntimePerPlatform(connector, from, to, userId); err != nil {
+		return nil, err
+	}
+	average, err := getAverageDaysToComplete(connector, from, to, userId)
+	if err != nil {
+		return nil, err
+	}
+	stats.AverageDaysToComplete = *average
+	if stats.Backlog, err = getBacklog(connector, userId); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func getStatusCounts(connector gotabase.Connector, from sql.NullTime, to sql.NullTime, userId uuid.UUID) ([]*StatusCount, error) {
+	query := `select p.status, count(1) ` + userPlaythroughs + ` group by p.status order by p.status`
+	return operations.QueryRows(connector, scanStatusCount, query, userId, from, to)
+}
+
+func getCompletionsPerMonth(connector gotabase.Connector, from sql.NullTime, to sql.NullTime, userId uuid.UUID) ([]*MonthCount, error) {
+	query := `select date_trunc('month', p.end_date) m, count(distinct p.game_id) ` + userCompletions + ` group by m order by m`
+	return operations.QueryRows(connector, scanMonthCount, query, userId, from, to, playthroughs.PlaythroughCompleted)
+}
+
+// getRuntimePerPlatform sums the runtime of playthroughs, preferring the one derived from play sessions when they exist.
+func getRuntimePerPlatform(connector gotabase.Connector, from sql.NullTime, to sql.NullTime, userId uuid.UUID) ([]*PlatformRuntime, error) {
+	query := `select pl.id, pl.name, coalesce(sum(coalesce(s.runtime, p.runtime_minutes)), 0)::integer minutes
+		from (select p.* ` + userPlaythroughs + `) p
+		join games g on g.id = p.game_id
+		join platforms pl on pl.id = g.platform_id
+		cross join lateral (
+			select ` + playthroughs.SessionRuntime + ` runtime
+			from play_sessions where playthrough_id = p.id
+		) s
+		group by pl.id, pl.name
+		order by minutes desc, pl.name`
+	return operations.QueryRows(connector, scanPlatformRuntime, query, userId, from, to)
+}
+
+func getAverageDaysToComplete(connector gotabase.Connector, from sql.NullTime, to sql.NullTime, userId uuid.UUID) (*sql.NullFloat64, error) {
+	query := `select avg(extract(epoch from p.end_date - p.start_date) / 86400)::float8 ` + userCompletions
+	return operations.QueryRow(connector, scanNullFloat, query, userId, from, to, playthroughs.PlaythroughCompleted)
+}
+
+// getBacklog counts the games, that have no completed, dropped or retired playthrough.
+func getBacklog(connector gotabase.Connector, userId uuid.UUID) (*Backlog, error) {
+	query := `select count(1) filter (where g.owned), count(1) filter (where not g.owned)
+		from games g
+		where g.user_id = $1 and g.deleted_at is null
+		and not exists(select from playthroughs p where p.game_id = g.id and p.deleted_at is null and p.status in ($2, $3, $4))`
+	return operations.QueryRow(connector, scanBacklog, query, userId, playthroughs.PlaythroughCompleted, playthroughs.PlaythroughDropped, playthroughs.PlaythroughRetired)
+}
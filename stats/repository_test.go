@@ -0,0 +1,159 @@
+package stats
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makePlatform(name string, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into platforms (id, name, short_name, user_id) values ($1, $2, left($2, 5), $3)`
+	_, err := getDatabase().Exec(query, id, name, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makeGame(platformId uuid.UUID, owned bool, userId uuid.UUID) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into games (id, title, platform_id, owned, release_date, released, user_id) values ($1, 'test', $2, $3, null, true, $4)`
+	_, err := getDatabase().Exec(query, id, platformId, owned, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makePlaythrough(gameId uuid.UUID, start time.Time, end *time.Time, status playthroughs.PlaythroughStatus, runtime int) uuid.UUID {
+	id := tests.GetRandomUuid()
+	query := `insert into playthroughs (id, game_id, start_date, end_date, status, runtime_minutes) values ($1, $2, $3, $4, $5, $6)`
+	_, err := getDatabase().Exec(query, id, gameId, start, end, status, runtime)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	value := date(year, month, day)
+	return &value
+}
+
+func nullDate(year int, month time.Month, day int) sql.NullTime {
+	return sql.NullTime{Valid: true, Time: date(year, month, day)}
+}
+
+func TestGetStats(t *testing.T) {
+	t.Run("Empty library", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		result, err := GetStats(sql.NullTime{}, sql.NullTime{}, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Statuses)
+		assert.Empty(t, result.CompletionsPerMonth)
+		assert.Empty(t, result.RuntimePerPlatform)
+		assert.False(t, result.AverageDaysToComplete.Valid)
+		assert.Equal(t, &Backlog{}, result.Backlog)
+	})
+
+	t.Run("Counts statuses of overlapping playthroughs", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		gameId := makeGame(makePlatform("pc", userId), true, userId)
+		makePlaythrough(gameId, date(2024, 1, 1), datePtr(2024, 2, 1), playthroughs.PlaythroughCompleted, 0)
+		makePlaythrough(gameId, date(2024, 3, 1), nil, playthroughs.PlaythroughInProgress, 0)
+		makePlaythrough(gameId, date(2024, 4, 1), nil, playthroughs.PlaythroughSuspended, 0)
+		makePlaythrough(gameId, date(2022, 1, 1), datePtr(2022, 2, 1), playthroughs.PlaythroughDropped, 0)
+		makePlaythrough(gameId, date(2025, 1, 1), nil, playthroughs.PlaythroughInProgress, 0)
+
+		result, err := GetStats(nullDate(2024, 1, 15), nullDate(2025, 1, 1), userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*StatusCount{
+			{Status: playthroughs.PlaythroughInProgress, Count: 1},
+			{Status: playthroughs.PlaythroughCompleted, Count: 1},
+			{Status: playthroughs.PlaythroughSuspended, Count: 1},
+		}, result.Statuses)
+	})
+
+	t.Run("Groups completions per month", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform("pc", userId)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 1, 1), datePtr(2024, 1, 11), playthroughs.PlaythroughCompleted, 0)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 1, 1), datePtr(2024, 1, 21), playthroughs.PlaythroughCompleted, 0)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 2, 1), datePtr(2024, 3, 2), playthroughs.PlaythroughCompleted, 0)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 2, 1), datePtr(2024, 3, 5), playthroughs.PlaythroughDropped, 0)
+		makePlaythrough(makeGame(platformId, true, userId), date(2023, 2, 1), datePtr(2023, 3, 5), playthroughs.PlaythroughCompleted, 0)
+
+		result, err := GetStats(nullDate(2024, 1, 1), sql.NullTime{}, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.CompletionsPerMonth, 2)
+		assert.Equal(t, time.January, result.CompletionsPerMonth[0].Month.Month())
+		assert.Equal(t, 2, result.CompletionsPerMonth[0].Count)
+		assert.Equal(t, time.March, result.CompletionsPerMonth[1].Month.Month())
+		assert.Equal(t, 1, result.CompletionsPerMonth[1].Count)
+		assert.True(t, result.AverageDaysToComplete.Valid)
+		assert.InDelta(t, 20.0, result.AverageDaysToComplete.Float64, 0.5)
+	})
+
+	t.Run("Sums runtime per platform", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		pcId := makePlatform("pc", userId)
+		switchId := makePlatform("switch", userId)
+		makePlaythrough(makeGame(pcId, true, userId), date(2024, 1, 1), nil, playthroughs.PlaythroughInProgress, 100)
+		makePlaythrough(makeGame(pcId, true, userId), date(2024, 1, 1), nil, playthroughs.PlaythroughInProgress, 50)
+		withSessions := makePlaythrough(makeGame(switchId, true, userId), date(2024, 1, 1), nil, playthroughs.PlaythroughInProgress, 10)
+		_, err := getDatabase().Exec(`insert into play_sessions (playthrough_id, start_time, duration_minutes) values ($1, $2, 40)`, withSessions, date(2024, 1, 2))
+		tests.PanicOnErr(err)
+
+		result, err := GetStats(sql.NullTime{}, sql.NullTime{}, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*PlatformRuntime{
+			{PlatformId: pcId, Name: "pc", Minutes: 150},
+			{PlatformId: switchId, Name: "switch", Minutes: 40},
+		}, result.RuntimePerPlatform)
+	})
+
+	t.Run("Counts unfinished games in backlog", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platformId := makePlatform("pc", userId)
+		makeGame(platformId, true, userId)
+		makeGame(platformId, false, userId)
+		makeGame(platformId, false, userId)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 1, 1), nil, playthroughs.PlaythroughInProgress, 0)
+		makePlaythrough(makeGame(platformId, true, userId), date(2024, 1, 1), datePtr(2024, 2, 1), playthroughs.PlaythroughCompleted, 0)
+		makePlaythrough(makeGame(platformId, false, userId), date(2024, 1, 1), datePtr(2024, 2, 1), playthroughs.PlaythroughDropped, 0)
+
+		result, err := GetStats(sql.NullTime{}, sql.NullTime{}, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Backlog{Owned: 2, Unowned: 2}, result.Backlog)
+	})
+
+	t.Run("Other users data not included", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		gameId := makeGame(makePlatform("pc", otherUserId), true, otherUserId)
+		makePlaythrough(gameId, date(2024, 1, 1), datePtr(2024, 2, 1), playthroughs.PlaythroughCompleted, 100)
+
+		result, err := GetStats(sql.NullTime{}, sql.NullTime{}, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Statuses)
+		assert.Empty(t, result.CompletionsPerMonth)
+		assert.Empty(t, result.RuntimePerPlatform)
+		assert.Equal(t, &Backlog{}, result.Backlog)
+	})
+}
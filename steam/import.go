@@ -0,0 +1,82 @@
+package steam
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+)
+
+// Import creates owned games on the platform for the apps, in a single transaction.
+//
+// Apps are skipped if any game of the user already has the same Steam app id.
+// If a game with the same title already exists on the platform, it is linked to the app instead of creating a new one,
+// so that it is recognised by the app id in the future.
+//
+// In dry-run mode all changes are made and then rolled back, so that the result shows what would be changed.
+func Import(apps []*App, platformId uuid.UUID, dryRun bool, userId uuid.UUID) (*ImportResult, error) {
+	if _, err := platforms.GetPlatform(platformId, userId); err != nil {
+		return nil, err
+	}
+	existing, err := games.GetAllGames(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	byAppId := make(map[int32]*games.Game)
+	byTitle := make(map[string]*games.Game)
+	for _, game := range existing {
+		if game.SteamAppId.Valid {
+			byAppId[game.SteamAppId.Int32] = game
+		}
+		if game.PlatformId == platformId {
+			byTitle[games.NormaliseTitle(game.Title)] = game
+		}
+	}
+
+	result := &ImportResult{DryRun: dryRun, Created: make([]*App, 0), Linked: make([]*App, 0), Skipped: make([]*App, 0)}
+	err = utils.RunInTransactionWithDryRun(dryRun, func(tx gotabase.Connector) error {
+		for _, app := range apps {
+			if byAppId[app.AppId] != nil {
+				result.Skipped = append(result.Skipped, app)
+				continue
+			}
+
+			if match := byTitle[games.NormaliseTitle(app.Name)]; match != nil {
+				if match.SteamAppId.Valid {
+					result.Skipped = append(result.Skipped, app)
+					continue
+				}
+				if err := games.SetSteamAppIdTx(tx, match.Id, app.AppId, userId); err != nil {
+					return err
+				}
+				match.SteamAppId = sql.NullInt32{Valid: true, Int32: app.AppId}
+				byAppId[app.AppId] = match
+				result.Linked = append(result.Linked, app)
+				continue
+			}
+
+			game := &games.Game{
+				PlatformId: platformId,
+				Title:      app.Name,
+				Owned:      true,
+				Released:   true,
+				SteamAppId: sql.NullInt32{Valid: true, Int32: app.AppId},
+			}
+			if err := games.CreateGameTx(tx, game, userId); err != nil {
+				return err
+			}
+			byAppId[app.AppId] = game
+			byTitle[games.NormaliseTitle(game.Title)] = game
+			result.Created = append(result.Created, app)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package steam
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testApps = []*App{
+	{AppId: 220, Name: "Half-Life 2"},
+	{AppId: 1145360, Name: "Hades"},
+	{AppId: 504230, Name: "Celeste"},
+}
+
+func TestImport(t *testing.T) {
+	t.Run("Games created", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := &platforms.Platform{Name: "Steam", ShortName: "STM"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+
+		result, err := Import(testApps, platform.Id, false, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Created, 3)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, userGames, 3)
+		assert.Equal(t, "Celeste", userGames[0].Title)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 504230}, userGames[0].SteamAppId)
+		assert.True(t, userGames[0].Owned)
+	})
+
+	t.Run("Existing games skipped or linked", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := &platforms.Platform{Name: "Steam", ShortName: "STM"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+		otherPlatform := &platforms.Platform{Name: "Steam Deck", ShortName: "SD"}
+		tests.PanicOnErr(platforms.CreatePlatform(otherPlatform, userId))
+		tests.PanicOnErr(games.CreateGame(&games.Game{PlatformId: otherPlatform.Id, Title: "Half-Life 2", SteamAppId: sql.NullInt32{Valid: true, Int32: 220}}, userId))
+		hades := &games.Game{PlatformId: platform.Id, Title: "HADES"}
+		tests.PanicOnErr(games.CreateGame(hades, userId))
+
+		result, err := Import(testApps, platform.Id, false, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*App{testApps[0]}, result.Skipped)
+		assert.Equal(t, []*App{testApps[1]}, result.Linked)
+		assert.Equal(t, []*App{testApps[2]}, result.Created)
+		linked, err := games.GetGame(hades.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, sql.NullInt32{Valid: true, Int32: 1145360}, linked.SteamAppId)
+	})
+
+	t.Run("Import can be repeated", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := &platforms.Platform{Name: "Steam", ShortName: "STM"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+		_, err := Import(testApps, platform.Id, false, userId)
+		tests.PanicOnErr(err)
+
+		result, err := Import(testApps, platform.Id, false, userId)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Created)
+		assert.Len(t, result.Skipped, 3)
+	})
+
+	t.Run("Dry run creates nothing", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(db)
+		platform := &platforms.Platform{Name: "Steam", ShortName: "STM"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+
+		result, err := Import(testApps, platform.Id, true, userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Created, 3)
+		userGames, err := games.GetAllGames(userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, userGames)
+	})
+
+	t.Run("Platform of another user", func(t *testing.T) {
+		db := tests.GetDatabaseWithCleanup(t)
+		platform := &platforms.Platform{Name: "Steam", ShortName: "STM"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, tests.MakeTestUserId(db)))
+
+		_, err := Import(testApps, platform.Id, false, tests.MakeTestUserId(db))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
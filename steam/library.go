@@ -0,0 +1,160 @@
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// toolNamePrefixes are prefixes of names of apps installed by Steam, that are not games.
+var toolNamePrefixes = []string{"Proton ", "Proton-", "Steam Linux Runtime", "Steamworks Common Redistributables", "SteamVR"}
+
+// ScanLibrary finds the apps installed in a Steam installation.
+//
+// The directory should be the steamapps directory of the installation, containing libraryfolders.vdf.
+// Apps from all library folders listed in that file are returned, as well as the ones found in the directory itself.
+// Folders that cannot be read are reported in the result instead of failing the scan.
+// Steam tools, such as Proton or runtimes, are not included.
+func ScanLibrary(steamappsDir string) (*Library, error) {
+	library := &Library{Apps: make([]*App, 0), MissingFolders: make([]string, 0)}
+	found := make(map[int32]bool)
+
+	if err := scanFolder(steamappsDir, library, found); err != nil {
+		return nil, err
+	}
+
+	folders, err := readLibraryFolders(filepath.Join(steamappsDir, "libraryfolders.vdf"))
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		dir := filepath.Join(folder, "steamapps")
+		if sameDir(dir, steamappsDir) {
+			continue
+		}
+		if err = scanFolder(dir, library, found); err != nil {
+			library.MissingFolders = append(library.MissingFolders, folder)
+		}
+	}
+
+	sort.Slice(library.Apps, func(i, j int) bool {
+		return library.Apps[i].Name < library.Apps[j].Name
+	})
+	return library, nil
+}
+
+// readLibraryFolders returns the paths of library folders listed in libraryfolders.vdf.
+// Both the current format, with a path entry per folder, and the legacy format, with paths as values, are supported.
+// A missing file results in no folders.
+func readLibraryFolders(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	root, err := ParseVdf(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	folders := root.Child("libraryfolders")
+	if folders == nil {
+		return nil, fmt.Errorf("failed to parse %s: libraryfolders entry is missing", path)
+	}
+
+	paths := make([]string, 0, len(folders.Children))
+	for _, folder := range folders.Children {
+		if _, err := strconv.Atoi(folder.Key); err != nil {
+			continue
+		}
+		if folder.Children == nil {
+			paths = append(paths, folder.Value)
+		} else if folderPath := folder.ChildValue("path"); folderPath != "" {
+			paths = append(paths, folderPath)
+		}
+	}
+	return paths, nil
+}
+
+func scanFolder(dir string, library *Library, found map[int32]bool) error {
+	manifests, err := filepath.Glob(filepath.Join(dir, "appmanifest_*.acf"))
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(dir); err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		app, err := readAppManifest(manifest)
+		if err != nil {
+			return err
+		}
+		if found[app.AppId] || isTool(app) {
+			continue
+		}
+		found[app.AppId] = true
+		library.Apps = append(library.Apps, app)
+	}
+	return nil
+}
+
+func readAppManifest(path string) (*App, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	app, err := ParseAppManifest(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return app, nil
+}
+
+// ParseAppManifest reads the id and name of an app from its appmanifest_*.acf file.
+func ParseAppManifest(reader io.Reader) (*App, error) {
+	root, err := ParseVdf(reader)
+	if err != nil {
+		return nil, err
+	}
+	state := root.Child("AppState")
+	if state == nil {
+		return nil, errors.New("AppState entry is missing")
+	}
+
+	appId, err := strconv.ParseInt(state.ChildValue("appid"), 10, 32)
+	if err != nil || appId <= 0 {
+		return nil, fmt.Errorf("app id %q is not valid", state.ChildValue("appid"))
+	}
+	name := strings.TrimSpace(state.ChildValue("name"))
+	if name == "" {
+		return nil, fmt.Errorf("app %d has no name", appId)
+	}
+	return &App{AppId: int32(appId), Name: name}, nil
+}
+
+func isTool(app *App) bool {
+	for _, prefix := range toolNamePrefixes {
+		if strings.HasPrefix(app.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameDir(a string, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
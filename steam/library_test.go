@@ -0,0 +1,101 @@
+package steam
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func appNames(library *Library) []string {
+	names := make([]string, len(library.Apps))
+	for i, app := range library.Apps {
+		names[i] = app.Name
+	}
+	return names
+}
+
+func TestParseAppManifest(t *testing.T) {
+	t.Run("Reads fixture", func(t *testing.T) {
+		file, err := os.Open("testdata/steamapps/appmanifest_220.acf")
+		assert.NoError(t, err)
+		defer file.Close()
+
+		app, err := ParseAppManifest(file)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &App{AppId: 220, Name: "Half-Life 2"}, app)
+	})
+
+	t.Run("Invalid app id", func(t *testing.T) {
+		_, err := ParseAppManifest(strings.NewReader(`"AppState" { "appid" "abc" "name" "Game" }`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing name", func(t *testing.T) {
+		_, err := ParseAppManifest(strings.NewReader(`"AppState" { "appid" "10" }`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Not an app manifest", func(t *testing.T) {
+		_, err := ParseAppManifest(strings.NewReader(`"libraryfolders" { }`))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestReadLibraryFolders(t *testing.T) {
+	t.Run("Current format", func(t *testing.T) {
+		folders, err := readLibraryFolders("testdata/steamapps/libraryfolders.vdf")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/nonexistent/ludivault/SteamLibrary"}, folders)
+	})
+
+	t.Run("Legacy format", func(t *testing.T) {
+		folders, err := readLibraryFolders("testdata/legacy/libraryfolders.vdf")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`D:\SteamLibrary`, `E:\Games\Steam`}, folders)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		folders, err := readLibraryFolders("testdata/library/steamapps/libraryfolders.vdf")
+
+		assert.NoError(t, err)
+		assert.Empty(t, folders)
+	})
+}
+
+func TestScanLibrary(t *testing.T) {
+	t.Run("Scans main folder", func(t *testing.T) {
+		library, err := ScanLibrary("testdata/steamapps")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Hades", "Half-Life 2"}, appNames(library))
+		assert.Equal(t, []string{"/nonexistent/ludivault/SteamLibrary"}, library.MissingFolders)
+	})
+
+	t.Run("Scans additional library folders", func(t *testing.T) {
+		libraryPath, err := filepath.Abs("testdata/library")
+		assert.NoError(t, err)
+		steamapps := t.TempDir()
+		content := "\"libraryfolders\"\n{\n\t\"0\"\n\t{\n\t\t\"path\"\t\"" + strings.ReplaceAll(libraryPath, `\`, `\\`) + "\"\n\t}\n}\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(steamapps, "libraryfolders.vdf"), []byte(content), 0o600))
+
+		library, err := ScanLibrary(steamapps)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`Celeste "Farewell" Edition`, "Half-Life 2"}, appNames(library))
+		assert.Empty(t, library.MissingFolders)
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		_, err := ScanLibrary("testdata/nonexistent")
+
+		assert.Error(t, err)
+	})
+}
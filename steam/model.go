@@ -0,0 +1,23 @@
+package steam
+
+// App is a single app installed from Steam.
+type App struct {
+	AppId int32
+	Name  string
+}
+
+// Library is the list of apps found in a Steam installation.
+type Library struct {
+	Apps []*App
+	// MissingFolders lists library folders that are listed in libraryfolders.vdf, but could not be read.
+	MissingFolders []string
+}
+
+// ImportResult summarises the changes made, or that would be made in dry-run mode, by Import.
+type ImportResult struct {
+	DryRun  bool
+	Created []*App
+	// Linked lists apps that matched an existing game by title, which was then linked to the app.
+	Linked  []*App
+	Skipped []*App
+}
@@ -0,0 +1,234 @@
+package steam
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeyValue is a single entry of a VDF document.
+// An entry holds either a string value or a list of child entries.
+type KeyValue struct {
+	Key      string
+	Value    string
+	Children []*KeyValue
+}
+
+// Child returns the first child entry with the key, compared case-insensitively, or nil if there is none.
+func (kv *KeyValue) Child(key string) *KeyValue {
+	for _, child := range kv.Children {
+		if strings.EqualFold(child.Key, key) {
+			return child
+		}
+	}
+	return nil
+}
+
+// ChildValue returns the value of the first child entry with the key, or an empty string if there is none.
+func (kv *KeyValue) ChildValue(key string) string {
+	if child := kv.Child(key); child != nil {
+		return child.Value
+	}
+	return ""
+}
+
+// ParseVdf reads a text VDF (Valve KeyValues) document, as used by Steam for libraryfolders.vdf and appmanifest_*.acf files.
+// The returned entry has no key and holds the top-level entries of the document as its children.
+//
+// Conditional suffixes (such as [$WIN32]) are ignored, and the entries they apply to are always included.
+func ParseVdf(reader io.Reader) (*KeyValue, error) {
+	parser := &vdfParser{reader: bufio.NewReader(reader), line: 1}
+	root := &KeyValue{}
+	if err := parser.parseChildren(root, false); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of file")
+
+type vdfParser struct {
+	reader *bufio.Reader
+	line   int
+}
+
+type tokenKind int
+
+const (
+	tokenString tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenEnd
+)
+
+func (p *vdfParser) parseChildren(parent *KeyValue, nested bool) error {
+	for {
+		kind, key, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case tokenEnd:
+			if nested {
+				return p.errorf("%v, missing closing brace", errUnexpectedEnd)
+			}
+			return nil
+		case tokenClose:
+			if !nested {
+				return p.errorf("unexpected closing brace")
+			}
+			return nil
+		case tokenOpen:
+			return p.errorf("unexpected opening brace, expected a key")
+		}
+
+		entry := &KeyValue{Key: key}
+		kind, value, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case tokenString:
+			entry.Value = value
+		case tokenOpen:
+			entry.Children = make([]*KeyValue, 0)
+			if err = p.parseChildren(entry, true); err != nil {
+				return err
+			}
+		default:
+			return p.errorf("expected a value for key %q", key)
+		}
+		parent.Children = append(parent.Children, entry)
+	}
+}
+
+// next returns the next token, skipping whitespace, comments and conditionals.
+func (p *vdfParser) next() (tokenKind, string, error) {
+	for {
+		r, err := p.read()
+		if errors.Is(err, io.EOF) {
+			return tokenEnd, "", nil
+		}
+		if err != nil {
+			return tokenEnd, "", err
+		}
+
+		switch {
+		case r == '\n':
+			p.line++
+		case r == ' ' || r == '\t' || r == '\r' || r == '\uFEFF':
+		case r == '{':
+			return tokenOpen, "", nil
+		case r == '}':
+			return tokenClose, "", nil
+		case r == '"':
+			value, err := p.readQuoted()
+			return tokenString, value, err
+		case r == '/':
+			if err = p.skipComment(); err != nil {
+				return tokenEnd, "", err
+			}
+		case r == '[':
+			if err = p.skipConditional(); err != nil {
+				return tokenEnd, "", err
+			}
+		default:
+			return tokenString, p.readUnquoted(r), nil
+		}
+	}
+}
+
+func (p *vdfParser) readQuoted() (string, error) {
+	var value strings.Builder
+	for {
+		r, err := p.read()
+		if errors.Is(err, io.EOF) {
+			return "", p.errorf("%v, missing closing quote", errUnexpectedEnd)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '"':
+			return value.String(), nil
+		case '\n':
+			p.line++
+			value.WriteRune(r)
+		case '\\':
+			escaped, err := p.read()
+			if err != nil {
+				return "", p.errorf("%v, unfinished escape sequence", errUnexpectedEnd)
+			}
+			switch escaped {
+			case 'n':
+				value.WriteRune('\n')
+			case 't':
+				value.WriteRune('\t')
+			default:
+				value.WriteRune(escaped)
+			}
+		default:
+			value.WriteRune(r)
+		}
+	}
+}
+
+func (p *vdfParser) readUnquoted(first rune) string {
+	var value strings.Builder
+	value.WriteRune(first)
+	for {
+		r, err := p.read()
+		if err != nil {
+			return value.String()
+		}
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '{' || r == '}' || r == '"' {
+			_ = p.reader.UnreadRune()
+			return value.String()
+		}
+		value.WriteRune(r)
+	}
+}
+
+func (p *vdfParser) skipComment() error {
+	r, err := p.read()
+	if err != nil || r != '/' {
+		return p.errorf("unexpected character '/'")
+	}
+	for {
+		r, err = p.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if r == '\n' {
+			p.line++
+			return nil
+		}
+	}
+}
+
+func (p *vdfParser) skipConditional() error {
+	for {
+		r, err := p.read()
+		if err != nil {
+			return p.errorf("%v, unfinished conditional", errUnexpectedEnd)
+		}
+		if r == ']' {
+			return nil
+		}
+	}
+}
+
+func (p *vdfParser) read() (rune, error) {
+	r, _, err := p.reader.ReadRune()
+	return r, err
+}
+
+func (p *vdfParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
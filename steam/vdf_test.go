@@ -0,0 +1,64 @@
+package steam
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseVdf(t *testing.T) {
+	t.Run("Parses nested entries", func(t *testing.T) {
+		content := `"root"
+{
+	"key"	"value"
+	"nested"
+	{
+		"inner"		"with \"quotes\" and \\ slash"
+	}
+	unquoted	value2
+}`
+
+		root, err := ParseVdf(strings.NewReader(content))
+
+		assert.NoError(t, err)
+		entry := root.Child("ROOT")
+		assert.NotNil(t, entry)
+		assert.Equal(t, "value", entry.ChildValue("key"))
+		assert.Equal(t, `with "quotes" and \ slash`, entry.Child("nested").ChildValue("inner"))
+		assert.Equal(t, "value2", entry.ChildValue("unquoted"))
+		assert.Equal(t, "", entry.ChildValue("missing"))
+	})
+
+	t.Run("Skips comments and conditionals", func(t *testing.T) {
+		content := "// comment\n\"a\" \"b\" [$WIN32] // trailing\n\"c\" { }\n"
+
+		root, err := ParseVdf(strings.NewReader(content))
+
+		assert.NoError(t, err)
+		assert.Len(t, root.Children, 2)
+		assert.Equal(t, "b", root.ChildValue("a"))
+		assert.Empty(t, root.Child("c").Children)
+		assert.NotNil(t, root.Child("c").Children)
+	})
+
+	cases := map[string]string{
+		"Missing closing brace": "\"a\"\n{\n\"b\" \"c\"\n",
+		"Unexpected brace":      "\"a\" \"b\"\n}\n",
+		"Missing value":         "\"a\"",
+		"Unterminated string":   "\"a\" \"b",
+		"Brace instead of key":  "{ }",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseVdf(strings.NewReader(content))
+
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Error reports line number", func(t *testing.T) {
+		_, err := ParseVdf(strings.NewReader("\"a\" \"b\"\n\"c\" \"d\"\n}"))
+
+		assert.ErrorContains(t, err, "line 3")
+	})
+}
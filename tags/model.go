@@ -0,0 +1,23 @@
+package tags
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	Id   uuid.UUID
+	Name string
+}
+
+func (t *Tag) SetId(id uuid.UUID) {
+	t.Id = id
+}
+
+func scanTag(row gotabase.Row) (*Tag, error) {
+	var tag Tag
+	if err := row.Scan(&tag.Id, &tag.Name); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
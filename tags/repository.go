@@ -0,0 +1,147 @@
+package tags
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetTags returns all tags defined by the user.
+func GetTags(userId uuid.UUID) ([]*Tag, error) {
+	query := `select id, name from tags where user_id = $1 order by name`
+	return operations.QueryRows(getDatabase(), scanTag, query, userId)
+}
+
+// GetTag returns a single [Tag] selected by id.
+func GetTag(id uuid.UUID, userId uuid.UUID) (*Tag, error) {
+	query := `select id, name from tags where id = $1 and user_id = $2`
+	return operations.QueryRow(getDatabase(), scanTag, query, id, userId)
+}
+
+// GetTagsForGames returns tags attached to each of the games provided, mapped by game id.
+// Games without any tags are not included in the result.
+func GetTagsForGames(gameIds []uuid.UUID, userId uuid.UUID) (map[uuid.UUID][]*Tag, error) {
+	result := make(map[uuid.UUID][]*Tag)
+	if len(gameIds) == 0 {
+		return result, nil
+	}
+
+	query := `select gt.game_id, t.id, t.name from games_tags gt join tags t on t.id = gt.tag_id where gt.game_id = any($1::uuid[]) and t.user_id = $2 order by t.name`
+	rows, err := getDatabase().QueryRows(query, pq.Array(gameIds), userId)
+	if err != nil {
+		return nil, operations.Errors.HandleError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameId uuid.UUID
+		var tag Tag
+		if err = rows.Scan(&gameId, &tag.Id, &tag.Name); err != nil {
+			return nil, operations.Errors.HandleError(err)
+		}
+		result[gameId] = append(result[gameId], &tag)
+	}
+	if err = utils.RowsErr(rows); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateTag creates a new [Tag] for the user.
+// Tag names must be unique for each user.
+func CreateTag(tag *Tag, userId uuid.UUID) error {
+	query := `insert into tags (name, user_id) values ($1, $2) returning id`
+	return operations.CreateRowWithId(getDatabase(), tag, query, tag.Name, userId)
+}
+
+// UpdateTag renames the [Tag] with the id as provided.
+func UpdateTag(tag *Tag, userId uuid.UUID) error {
+	query := `update tags set name = $3 where id = $1 and user_id = $2`
+	return operations.UpdateRow(getDatabase(), query, tag.Id, userId, tag.Name)
+}
+
+// DeleteTag removes a single [Tag], detaching it from all games.
+func DeleteTag(id uuid.UUID, userId uuid.UUID) error {
+	query := `delete from tags where id = $1 and user_id = $2`
+	return operations.DeleteRow(getDatabase(), query, id, userId)
+}
+
+// AttachTag adds the [Tag] to the game, which creates a new version of the game.
+// Attaching a tag that is already attached to the game has no effect.
+//
+// If the game version is not 0, the tag is only attached if the game still has that version, VersionMismatchErr from utils is returned otherwise.
+func AttachTag(gameId uuid.UUID, tagId uuid.UUID, gameVersion int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		if err := lockGame(tx, gameId, gameVersion, userId); err != nil {
+			return err
+		}
+
+		query := `insert into games_tags (game_id, tag_id)
+			select g.id, t.id from games g join tags t on t.user_id = g.user_id
+			where g.id = $1 and t.id = $2 and g.user_id = $3 and g.deleted_at is null
+			on conflict do nothing`
+		result, err := tx.Exec(query, gameId, tagId, userId)
+		if err != nil {
+			return operations.Errors.HandleError(err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return operations.Errors.HandleError(err)
+		}
+		if affected == 0 && !isAttached(tx, gameId, tagId, userId) {
+			return operations.Errors.DataNotFoundErr
+		}
+		return nil
+	})
+}
+
+// DetachTag removes the [Tag] from the game, which creates a new version of the game.
+//
+// If the game version is not 0, the tag is only detached if the game still has that version, VersionMismatchErr from utils is returned otherwise.
+func DetachTag(gameId uuid.UUID, tagId uuid.UUID, gameVersion int, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		if err := lockGame(tx, gameId, gameVersion, userId); err != nil {
+			return err
+		}
+
+		query := `delete from games_tags gt using tags t where gt.tag_id = t.id and gt.game_id = $1 and gt.tag_id = $2 and t.user_id = $3`
+		return operations.DeleteRow(tx, query, gameId, tagId, userId)
+	})
+}
+
+// lockGame locks the game of the user until the end of the transaction, so that it cannot change before its tags are.
+// If the version is not 0, the game must still have that version.
+func lockGame(tx gotabase.Connector, gameId uuid.UUID, version int, userId uuid.UUID) error {
+	query := `select id from games where id = $1 and user_id = $2 and deleted_at is null and ($3::integer = 0 or version = $3) for update`
+	if _, err := operations.QueryRow(tx, scanId, query, gameId, userId, version); err != nil {
+		return utils.CheckVersionMismatch(err, version, func() bool {
+			_, err := operations.QueryRow(tx, scanId, query, gameId, userId, 0)
+			return err == nil
+		})
+	}
+	return nil
+}
+
+func isAttached(connector gotabase.Connector, gameId uuid.UUID, tagId uuid.UUID, userId uuid.UUID) bool {
+	query := `select exists(select from games_tags gt join tags t on t.id = gt.tag_id where gt.game_id = $1 and gt.tag_id = $2 and t.user_id = $3)`
+	row, err := connector.QueryRow(query, gameId, tagId, userId)
+	if err != nil {
+		return false
+	}
+	var exists bool
+	if err = row.Scan(&exists); err != nil {
+		return false
+	}
+	return exists
+}
+
+func scanId(row gotabase.Row) (*uuid.UUID, error) {
+	var id uuid.UUID
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
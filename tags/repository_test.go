@@ -0,0 +1,204 @@
+package tags
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func makeGame(userId uuid.UUID) uuid.UUID {
+	platformId := tests.GetRandomUuid()
+	_, err := getDatabase().Exec(`insert into platforms (id, name, short_name, user_id) values ($1, 'aa', 'aa', $2)`, platformId, userId)
+	tests.PanicOnErr(err)
+	id := tests.GetRandomUuid()
+	_, err = getDatabase().Exec(`insert into games (id, title, platform_id, released, user_id) values ($1, 'game', $2, true, $3)`, id, platformId, userId)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func makeTestTag(name string, userId uuid.UUID) Tag {
+	tag := Tag{Name: name}
+	tests.PanicOnErr(CreateTag(&tag, userId))
+	return tag
+}
+
+func TestCreateTag(t *testing.T) {
+	t.Run("New tag created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := Tag{Name: "co-op"}
+
+		err := CreateTag(&tag, userId)
+
+		assert.NoError(t, err)
+		dbTag, err := GetTag(tag.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, tag, *dbTag)
+	})
+
+	t.Run("Tag already exists", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		makeTestTag("co-op", userId)
+		tag := Tag{Name: "co-op"}
+
+		err := CreateTag(&tag, userId)
+
+		assert.Equal(t, operations.Errors.DataAlreadyExistErr, err)
+	})
+
+	t.Run("Same tag created by two users", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		makeTestTag("co-op", tests.MakeTestUserId(getDatabase()))
+		tag := Tag{Name: "co-op"}
+
+		err := CreateTag(&tag, tests.MakeTestUserId(getDatabase()))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetTags(t *testing.T) {
+	t.Run("Only tags of the user returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		makeTestTag("other", tests.MakeTestUserId(getDatabase()))
+
+		list, err := GetTags(userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*Tag{&tag}, list)
+	})
+}
+
+func TestUpdateTag(t *testing.T) {
+	t.Run("Tag renamed", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		tag.Name = "multiplayer"
+
+		err := UpdateTag(&tag, userId)
+
+		assert.NoError(t, err)
+		dbTag, err := GetTag(tag.Id, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, tag, *dbTag)
+	})
+
+	t.Run("User not authorised", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		tag := makeTestTag("co-op", tests.MakeTestUserId(getDatabase()))
+		tag.Name = "multiplayer"
+
+		err := UpdateTag(&tag, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	t.Run("Tag deleted and detached", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		gameId := makeGame(userId)
+		tests.PanicOnErr(AttachTag(gameId, tag.Id, 0, userId))
+
+		err := DeleteTag(tag.Id, userId)
+
+		assert.NoError(t, err)
+		gameTags, err := GetTagsForGames([]uuid.UUID{gameId}, userId)
+		tests.PanicOnErr(err)
+		assert.Empty(t, gameTags)
+	})
+}
+
+func TestAttachTag(t *testing.T) {
+	t.Run("Tag attached", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		gameId := makeGame(userId)
+
+		err := AttachTag(gameId, tag.Id, 0, userId)
+
+		assert.NoError(t, err)
+		gameTags, err := GetTagsForGames([]uuid.UUID{gameId}, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, []*Tag{&tag}, gameTags[gameId])
+	})
+
+	t.Run("Attaching twice has no effect", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		gameId := makeGame(userId)
+		tests.PanicOnErr(AttachTag(gameId, tag.Id, 0, userId))
+
+		err := AttachTag(gameId, tag.Id, 0, userId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Game version mismatch - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		gameId := makeGame(userId)
+		tests.PanicOnErr(AttachTag(gameId, makeTestTag("co-op", userId).Id, 0, userId))
+
+		err := AttachTag(gameId, makeTestTag("indie", userId).Id, 1, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+	})
+
+	t.Run("Tag of another user - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", tests.MakeTestUserId(getDatabase()))
+
+		err := AttachTag(makeGame(userId), tag.Id, 0, userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestDetachTag(t *testing.T) {
+	t.Run("Tag detached", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		gameId := makeGame(userId)
+		tests.PanicOnErr(AttachTag(gameId, tag.Id, 0, userId))
+
+		err := DetachTag(gameId, tag.Id, 0, userId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Game version mismatch - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+		gameId := makeGame(userId)
+		tests.PanicOnErr(AttachTag(gameId, tag.Id, 0, userId))
+
+		err := DetachTag(gameId, tag.Id, 1, userId)
+
+		assert.Equal(t, utils.VersionMismatchErr, err)
+	})
+
+	t.Run("Tag not attached - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tag := makeTestTag("co-op", userId)
+
+		err := DetachTag(makeGame(userId), tag.Id, 0, userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
@@ -0,0 +1,7 @@
+package timers
+
+import "errors"
+
+var (
+	TimerAlreadyRunningErr = errors.New("another timer is already running")
+)
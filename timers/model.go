@@ -0,0 +1,31 @@
+package timers
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Timer is a running measurement of playtime of a playthrough.
+type Timer struct {
+	UserId        uuid.UUID
+	PlaythroughId uuid.UUID
+	StartedAt     time.Time
+	// Elapsed is calculated by the database at the time of reading, so that it does not depend on the local clock.
+	Elapsed time.Duration
+}
+
+// ElapsedMinutes returns the number of full minutes the timer has been running for.
+func (t *Timer) ElapsedMinutes() int {
+	return int(t.Elapsed / time.Minute)
+}
+
+func scanTimer(row gotabase.Row) (*Timer, error) {
+	var timer Timer
+	var elapsedSeconds int64
+	if err := row.Scan(&timer.UserId, &timer.PlaythroughId, &timer.StartedAt, &elapsedSeconds); err != nil {
+		return nil, err
+	}
+	timer.Elapsed = time.Duration(elapsedSeconds) * time.Second
+	return &timer, nil
+}
@@ -0,0 +1,101 @@
+package timers
+
+import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+)
+
+const timerColumns = `user_id, playthrough_id, started_at, extract(epoch from now() - started_at)::bigint`
+
+// GetTimer returns the timer running for the playthrough.
+func GetTimer(playthroughId uuid.UUID, userId uuid.UUID) (*Timer, error) {
+	query := `select ` + timerColumns + ` from playthrough_timers where user_id = $1 and playthrough_id = $2`
+	return operations.QueryRow(getDatabase(), scanTimer, query, userId, playthroughId)
+}
+
+// StartTimer starts a new timer for the playthrough.
+//
+// Only a single timer can run for a user at a time, TimerAlreadyRunningErr is returned otherwise.
+// Suspended playthroughs are moved back to being in progress.
+func StartTimer(playthroughId uuid.UUID, userId uuid.UUID) (*Timer, error) {
+	var timer *Timer
+	err := utils.RunInTransaction(func(tx gotabase.Connector) error {
+		var err error
+		timer, err = startTimer(tx, playthroughId, userId)
+		return err
+	})
+	return timer, err
+}
+
+func startTimer(connector gotabase.Connector, playthroughId uuid.UUID, userId uuid.UUID) (*Timer, error) {
+	exists, err := operations.QueryRow(connector, scanBool, `select check_user_playthrough($1, $2)`, userId, playthroughId)
+	if err != nil {
+		return nil, err
+	}
+	if !*exists {
+		return nil, operations.Errors.DataNotFoundErr
+	}
+
+	query := `insert into playthrough_timers (user_id, playthrough_id) values ($1, $2) on conflict (user_id) do nothing returning ` + timerColumns
+	timer, err := operations.QueryRow(connector, scanTimer, query, userId, playthroughId)
+	if err != nil {
+		if errors.Is(err, operations.Errors.DataNotFoundErr) {
+			return nil, TimerAlreadyRunningErr
+		}
+		return nil, err
+	}
+
+	query = `update playthroughs set status = $2 where id = $1 and status = $3`
+	if _, err = connector.Exec(query, playthroughId, playthroughs.PlaythroughInProgress, playthroughs.PlaythroughSuspended); err != nil {
+		return nil, operations.Errors.HandleError(err)
+	}
+
+	return timer, nil
+}
+
+// StopTimer stops the timer running for the playthrough and adds the elapsed minutes to its runtime.
+//
+// If the playthrough has play sessions, its runtime is derived from them,
+// so the timed period is additionally recorded as a new play session.
+func StopTimer(playthroughId uuid.UUID, userId uuid.UUID) (*Timer, error) {
+	var timer *Timer
+	err := utils.RunInTransaction(func(tx gotabase.Connector) error {
+		var err error
+		timer, err = stopTimer(tx, playthroughId, userId)
+		return err
+	})
+	return timer, err
+}
+
+func stopTimer(connector gotabase.Connector, playthroughId uuid.UUID, userId uuid.UUID) (*Timer, error) {
+	query := `delete from playthrough_timers where user_id = $1 and playthrough_id = $2 returning ` + timerColumns
+	timer, err := operations.QueryRow(connector, scanTimer, query, userId, playthroughId)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `update playthroughs set runtime_minutes = coalesce(runtime_minutes, 0) + $2 where id = $1`
+	if err = operations.UpdateRow(connector, query, playthroughId, timer.ElapsedMinutes()); err != nil {
+		return nil, err
+	}
+
+	query = `insert into play_sessions (playthrough_id, start_time, end_time)
+		select $1, $2, $2::timestamptz + make_interval(secs => $3) where exists(select from play_sessions where playthrough_id = $1)`
+	if _, err = connector.Exec(query, playthroughId, timer.StartedAt, timer.Elapsed.Seconds()); err != nil {
+		return nil, operations.Errors.HandleError(err)
+	}
+
+	return timer, nil
+}
+
+func scanBool(row gotabase.Row) (*bool, error) {
+	var value bool
+	if err := row.Scan(&value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
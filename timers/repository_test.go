@@ -0,0 +1,204 @@
+package timers
+
+import (
+	"fmt"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// platformCount keeps names and short names of the test platforms unique.
+var platformCount int
+
+func makePlaythrough(userId uuid.UUID, status playthroughs.PlaythroughStatus) uuid.UUID {
+	platformCount++
+	platformId := tests.GetRandomUuid()
+	_, err := getDatabase().Exec(`insert into platforms (id, name, short_name, user_id) values ($1, $2, $2, $3)`, platformId, fmt.Sprintf("p%d", platformCount), userId)
+	tests.PanicOnErr(err)
+	gameId := tests.GetRandomUuid()
+	_, err = getDatabase().Exec(`insert into games (id, title, platform_id, release_date, released, user_id) values ($1, 'test', $2, null, true, $3)`, gameId, platformId, userId)
+	tests.PanicOnErr(err)
+	id := tests.GetRandomUuid()
+	_, err = getDatabase().Exec(`insert into playthroughs (id, game_id, start_date, status, runtime_minutes) values ($1, $2, now(), $3, 10)`, id, gameId, status)
+	tests.PanicOnErr(err)
+	return id
+}
+
+func backdateTimer(userId uuid.UUID, by time.Duration) {
+	_, err := getDatabase().Exec(`update playthrough_timers set started_at = now() - make_interval(secs => $2) where user_id = $1`, userId, by.Seconds())
+	tests.PanicOnErr(err)
+}
+
+func getPlaythroughState(id uuid.UUID) (playthroughs.PlaythroughStatus, int) {
+	row, err := getDatabase().QueryRow(`select status, runtime_minutes from playthroughs where id = $1`, id)
+	tests.PanicOnErr(err)
+	var status playthroughs.PlaythroughStatus
+	var runtime int
+	tests.PanicOnErr(row.Scan(&status, &runtime))
+	return status, runtime
+}
+
+func TestStartTimer(t *testing.T) {
+	t.Run("Timer started", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughInProgress)
+
+		timer, err := StartTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, playthroughId, timer.PlaythroughId)
+		assert.Equal(t, userId, timer.UserId)
+		dbTimer, err := GetTimer(playthroughId, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, timer.StartedAt, dbTimer.StartedAt)
+	})
+
+	t.Run("Suspended playthrough resumed", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughSuspended)
+
+		_, err := StartTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		status, _ := getPlaythroughState(playthroughId)
+		assert.Equal(t, playthroughs.PlaythroughInProgress, status)
+	})
+
+	t.Run("Completed playthrough keeps status", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughCompleted)
+
+		_, err := StartTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		status, _ := getPlaythroughState(playthroughId)
+		assert.Equal(t, playthroughs.PlaythroughCompleted, status)
+	})
+
+	t.Run("Another timer running returns error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, err := StartTimer(makePlaythrough(userId, playthroughs.PlaythroughInProgress), userId)
+		tests.PanicOnErr(err)
+
+		_, err = StartTimer(makePlaythrough(userId, playthroughs.PlaythroughInProgress), userId)
+
+		assert.Equal(t, TimerAlreadyRunningErr, err)
+	})
+
+	t.Run("Timers of other users are independent", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		_, err := StartTimer(makePlaythrough(otherUserId, playthroughs.PlaythroughInProgress), otherUserId)
+		tests.PanicOnErr(err)
+
+		_, err = StartTimer(makePlaythrough(userId, playthroughs.PlaythroughInProgress), userId)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughSuspended)
+
+		_, err := StartTimer(playthroughId, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		status, _ := getPlaythroughState(playthroughId)
+		assert.Equal(t, playthroughs.PlaythroughSuspended, status)
+	})
+}
+
+func TestGetTimer(t *testing.T) {
+	t.Run("Returns elapsed time", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughInProgress)
+		_, err := StartTimer(playthroughId, userId)
+		tests.PanicOnErr(err)
+		backdateTimer(userId, 25*time.Minute)
+
+		timer, err := GetTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25, timer.ElapsedMinutes())
+	})
+
+	t.Run("Timer not running", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		_, err := GetTimer(makePlaythrough(userId, playthroughs.PlaythroughInProgress), userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestStopTimer(t *testing.T) {
+	t.Run("Elapsed minutes added to runtime", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughInProgress)
+		_, err := StartTimer(playthroughId, userId)
+		tests.PanicOnErr(err)
+		backdateTimer(userId, 42*time.Minute)
+
+		timer, err := StopTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, timer.ElapsedMinutes())
+		_, runtime := getPlaythroughState(playthroughId)
+		assert.Equal(t, 52, runtime)
+		_, err = GetTimer(playthroughId, userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Play session recorded when playthrough has sessions", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughInProgress)
+		_, err := getDatabase().Exec(`insert into play_sessions (playthrough_id, start_time, duration_minutes) values ($1, now() - interval '2 days', 30)`, playthroughId)
+		tests.PanicOnErr(err)
+		_, err = StartTimer(playthroughId, userId)
+		tests.PanicOnErr(err)
+		backdateTimer(userId, 15*time.Minute)
+
+		_, err = StopTimer(playthroughId, userId)
+
+		assert.NoError(t, err)
+		playthrough, err := playthroughs.GetPlaythrough(playthroughId, userId)
+		tests.PanicOnErr(err)
+		assert.Equal(t, 2, playthrough.SessionCount)
+		assert.Equal(t, int32(45), playthrough.Runtime.Int32)
+	})
+
+	t.Run("Timer not running", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		_, err := StopTimer(makePlaythrough(userId, playthroughs.PlaythroughInProgress), userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("User not authorised for playthrough", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		playthroughId := makePlaythrough(userId, playthroughs.PlaythroughInProgress)
+		_, err := StartTimer(playthroughId, userId)
+		tests.PanicOnErr(err)
+
+		_, err = StopTimer(playthroughId, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
@@ -0,0 +1,77 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+type TokenScope int8
+
+const (
+	TokenScopeRead TokenScope = iota
+	TokenScopeReadWrite
+)
+
+const (
+	secretPrefix = "ldv_"
+	secretLength = 32
+)
+
+type Token struct {
+	Id         uuid.UUID
+	Name       string
+	Scope      TokenScope
+	CreatedAt  time.Time
+	LastUsedAt sql.NullTime
+	ExpiresAt  sql.NullTime
+}
+
+func (t *Token) SetId(id uuid.UUID) {
+	t.Id = id
+}
+
+// AllowsWrites returns true if the [Token] can be used to modify user data.
+func (t *Token) AllowsWrites() bool {
+	return t.Scope == TokenScopeReadWrite
+}
+
+// UsedToken is a [Token] resolved from the secret sent by a client.
+type UsedToken struct {
+	Token
+	UserId uuid.UUID
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func scanToken(row gotabase.Row) (*Token, error) {
+	var token Token
+	if err := row.Scan(&token.Id, &token.Name, &token.Scope, &token.CreatedAt, &token.LastUsedAt, &token.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func scanUsedToken(row gotabase.Row) (*UsedToken, error) {
+	var token UsedToken
+	if err := row.Scan(&token.Id, &token.Name, &token.Scope, &token.CreatedAt, &token.LastUsedAt, &token.ExpiresAt, &token.UserId); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
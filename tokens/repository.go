@@ -0,0 +1,47 @@
+package tokens
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetTokens returns all API tokens of the user, including the expired ones.
+func GetTokens(userId uuid.UUID) ([]*Token, error) {
+	query := `select id, name, scope, created_at, last_used_at, expires_at from api_tokens where user_id = $1 order by created_at desc`
+	return operations.QueryRows(getDatabase(), scanToken, query, userId)
+}
+
+// CreateToken creates a new API [Token] for the user and sets its id in the provided struct.
+// The returned secret is only stored as a hash, so it cannot be retrieved again later.
+func CreateToken(token *Token, userId uuid.UUID) (string, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		log.Warnf("Failed to generate token secret: %v", err)
+		return "", err
+	}
+
+	query := `insert into api_tokens (user_id, name, token_hash, scope, expires_at) values ($1, $2, $3, $4, $5) returning id, created_at`
+	err = operations.CreateRowWithScan(getDatabase(), token, func(row gotabase.Row, token *Token) error {
+		return row.Scan(&token.Id, &token.CreatedAt)
+	}, query, userId, token.Name, hashSecret(secret), token.Scope, token.ExpiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// GetTokenBySecret returns the [UsedToken] matching the secret, as long as it was not revoked and has not expired.
+// Last used time of the token is updated as well.
+func GetTokenBySecret(secret string) (*UsedToken, error) {
+	query := `update api_tokens set last_used_at = now() where token_hash = $1 and (expires_at is null or expires_at > now()) returning id, name, scope, created_at, last_used_at, expires_at, user_id`
+	return operations.QueryRow(getDatabase(), scanUsedToken, query, hashSecret(secret))
+}
+
+// RevokeToken removes a single API [Token] of the user.
+func RevokeToken(id uuid.UUID, userId uuid.UUID) error {
+	query := `delete from api_tokens where id = $1 and user_id = $2`
+	return operations.DeleteRow(getDatabase(), query, id, userId)
+}
@@ -0,0 +1,122 @@
+package tokens
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeTestToken(userId uuid.UUID, expiresAt sql.NullTime) (*Token, string) {
+	token := &Token{
+		Name:      "test token",
+		Scope:     TokenScopeReadWrite,
+		ExpiresAt: expiresAt,
+	}
+	secret, err := CreateToken(token, userId)
+	tests.PanicOnErr(err)
+	return token, secret
+}
+
+func TestCreateToken(t *testing.T) {
+	t.Run("New token created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		token := &Token{Name: "test token", Scope: TokenScopeRead}
+
+		secret, err := CreateToken(token, userId)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, token.Id)
+		assert.True(t, strings.HasPrefix(secret, secretPrefix))
+		var storedHash string
+		row, err := getDatabase().QueryRow("select token_hash from api_tokens where id = $1", token.Id)
+		tests.PanicOnErr(err)
+		tests.PanicOnErr(row.Scan(&storedHash))
+		assert.Equal(t, hashSecret(secret), storedHash)
+	})
+
+	t.Run("Secrets are unique", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		_, secret1 := makeTestToken(userId, sql.NullTime{})
+		_, secret2 := makeTestToken(userId, sql.NullTime{})
+
+		assert.NotEqual(t, secret1, secret2)
+	})
+}
+
+func TestGetTokens(t *testing.T) {
+	t.Run("Only tokens of the user returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		token, _ := makeTestToken(userId, sql.NullTime{})
+		makeTestToken(tests.MakeTestUserId(getDatabase()), sql.NullTime{})
+
+		list, err := GetTokens(userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, token.Id, list[0].Id)
+	})
+}
+
+func TestGetTokenBySecret(t *testing.T) {
+	t.Run("Valid token - returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		token, secret := makeTestToken(userId, sql.NullTime{})
+
+		dbToken, err := GetTokenBySecret(secret)
+
+		assert.NoError(t, err)
+		assert.Equal(t, token.Id, dbToken.Id)
+		assert.Equal(t, userId, dbToken.UserId)
+		assert.True(t, dbToken.LastUsedAt.Valid)
+	})
+
+	t.Run("Expired token - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		_, secret := makeTestToken(tests.MakeTestUserId(getDatabase()), sql.NullTime{Valid: true, Time: time.Now().Add(-time.Hour)})
+
+		_, err := GetTokenBySecret(secret)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Unknown secret - error", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+
+		_, err := GetTokenBySecret("ldv_unknown")
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	t.Run("Token exists - revoked", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		token, secret := makeTestToken(userId, sql.NullTime{})
+
+		err := RevokeToken(token.Id, userId)
+
+		assert.NoError(t, err)
+		_, err = GetTokenBySecret(secret)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("User not authorised", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		token, _ := makeTestToken(tests.MakeTestUserId(getDatabase()), sql.NullTime{})
+
+		err := RevokeToken(token.Id, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
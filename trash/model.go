@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ItemType string
+
+const (
+	ItemGame        ItemType = "game"
+	ItemPlatform    ItemType = "platform"
+	ItemPlaythrough ItemType = "playthrough"
+)
+
+// Item is a single game, platform or playthrough in the trash.
+type Item struct {
+	Type ItemType
+	Id   uuid.UUID
+	// Name is the name of the platform, or the title of the game, which is used for its playthroughs as well.
+	Name      string
+	DeletedAt time.Time
+}
+
+func scanItem(row gotabase.Row) (*Item, error) {
+	var item Item
+	if err := row.Scan(&item.Type, &item.Id, &item.Name, &item.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
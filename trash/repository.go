@@ -0,0 +1,98 @@
+package trash
+
+import (
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/utils"
+	"github.com/google/uuid"
+	"time"
+)
+
+// GetItems returns all items of the user in the trash, from the most recently deleted ones.
+//
+// Playthroughs of games in the trash are only listed if they were deleted on their own.
+func GetItems(userId uuid.UUID) ([]*Item, error) {
+	query := `select 'platform', id, name, deleted_at from platforms where user_id = $1 and deleted_at is not null
+		union all
+		select 'game', id, title, deleted_at from games where user_id = $1 and deleted_at is not null
+		union all
+		select 'playthrough', p.id, g.title, p.deleted_at from playthroughs p join games g on g.id = p.game_id where g.user_id = $1 and p.deleted_at is not null
+		order by 4 desc, 3, 2`
+	return operations.QueryRows(getDatabase(), scanItem, query, userId)
+}
+
+// Restore takes the item out of the trash.
+//
+// Items are restored along with what they depend on: a playthrough along with its game, and a game along with its platform.
+// Restoring a platform fails with DataAlreadyExistErr if another platform of the user uses its name or short name by then.
+func Restore(itemType ItemType, id uuid.UUID, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		switch itemType {
+		case ItemPlatform:
+			query := `update platforms set deleted_at = null where id = $1 and user_id = $2 and deleted_at is not null`
+			return operations.UpdateRow(tx, query, id, userId)
+		case ItemGame:
+			if err := restoreGameParents(tx, id, userId); err != nil {
+				return err
+			}
+			query := `update games set deleted_at = null where id = $1 and user_id = $2 and deleted_at is not null`
+			return operations.UpdateRow(tx, query, id, userId)
+		case ItemPlaythrough:
+			gameId, err := operations.QueryRow(tx, scanId, `select p.game_id from playthroughs p join games g on g.id = p.game_id where p.id = $1 and g.user_id = $2`, id, userId)
+			if err != nil {
+				return err
+			}
+			if err = restoreGameParents(tx, *gameId, userId); err != nil {
+				return err
+			}
+			if _, err = tx.Exec(`update games set deleted_at = null where id = $1 and deleted_at is not null`, *gameId); err != nil {
+				return operations.Errors.HandleError(err)
+			}
+			query := `update playthroughs set deleted_at = null where id = $1 and deleted_at is not null`
+			return operations.UpdateRow(tx, query, id)
+		default:
+			return operations.Errors.DataNotFoundErr
+		}
+	})
+}
+
+// restoreGameParents restores the platform of the game, if it is in the trash.
+func restoreGameParents(tx gotabase.Connector, gameId uuid.UUID, userId uuid.UUID) error {
+	query := `update platforms p set deleted_at = null from games g
+		where g.id = $1 and g.user_id = $2 and p.id = g.platform_id and p.deleted_at is not null`
+	_, err := tx.Exec(query, gameId, userId)
+	return operations.Errors.HandleError(err)
+}
+
+// PurgeTx removes items of all users, which were moved to the trash before the time provided, for good, returning the number of items removed.
+// Playthroughs of purged games are removed along with them, and are not counted.
+// Platforms are only purged once no game in the trash uses them anymore.
+func PurgeTx(connector gotabase.Connector, before time.Time) (int, error) {
+	queries := []string{
+		`delete from playthroughs where deleted_at < $1`,
+		`delete from games where deleted_at < $1`,
+		`delete from platforms p where deleted_at < $1 and not exists(select from games g where g.platform_id = p.id)`,
+	}
+
+	total := 0
+	for _, query := range queries {
+		result, err := connector.Exec(query, before)
+		if err != nil {
+			return 0, operations.Errors.HandleError(err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, operations.Errors.HandleError(err)
+		}
+		total += int(count)
+	}
+	return total, nil
+}
+
+func scanId(row gotabase.Row) (*uuid.UUID, error) {
+	var id uuid.UUID
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,162 @@
+package trash
+
+import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/games"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/KowalskiPiotr98/ludivault/platforms"
+	"github.com/KowalskiPiotr98/ludivault/playthroughs"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makeGame(userId uuid.UUID) (*platforms.Platform, *games.Game) {
+	platform := &platforms.Platform{Name: "test", ShortName: "tst"}
+	tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+	game := &games.Game{PlatformId: platform.Id, Title: "test game", Released: true}
+	tests.PanicOnErr(games.CreateGame(game, userId))
+	return platform, game
+}
+
+func makePlaythrough(gameId uuid.UUID, userId uuid.UUID) *playthroughs.Playthrough {
+	playthrough := &playthroughs.Playthrough{GameId: gameId, StartDate: tests.GetRandomTestTime()}
+	tests.PanicOnErr(playthroughs.CreatePlaythrough(playthrough, userId))
+	return playthrough
+}
+
+func TestGetItems(t *testing.T) {
+	t.Run("Deleted items of the user listed", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		playthrough := makePlaythrough(game.Id, userId)
+		makePlaythrough(game.Id, userId)
+		tests.PanicOnErr(playthroughs.DeletePlaythrough(playthrough.Id, 0, userId))
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+		_, otherGame := makeGame(tests.MakeTestUserId(getDatabase()))
+		_, err := getDatabase().Exec(`update games set deleted_at = now() where id = $1`, otherGame.Id)
+		tests.PanicOnErr(err)
+
+		items, err := GetItems(userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.ElementsMatch(t, []ItemType{ItemGame, ItemPlaythrough}, []ItemType{items[0].Type, items[1].Type})
+		for _, item := range items {
+			assert.Equal(t, "test game", item.Name)
+		}
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("Game restored with its playthroughs", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		playthrough := makePlaythrough(game.Id, userId)
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+
+		err := Restore(ItemGame, game.Id, userId)
+
+		assert.NoError(t, err)
+		_, err = games.GetGame(game.Id, userId)
+		assert.NoError(t, err)
+		_, err = playthroughs.GetPlaythrough(playthrough.Id, userId)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Playthrough restored with its game and platform", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform, game := makeGame(userId)
+		playthrough := makePlaythrough(game.Id, userId)
+		tests.PanicOnErr(playthroughs.DeletePlaythrough(playthrough.Id, 0, userId))
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+		tests.PanicOnErr(platforms.DeletePlatform(platform.Id, 0, userId))
+
+		err := Restore(ItemPlaythrough, playthrough.Id, userId)
+
+		assert.NoError(t, err)
+		_, err = playthroughs.GetPlaythrough(playthrough.Id, userId)
+		assert.NoError(t, err)
+		_, err = platforms.GetPlatform(platform.Id, userId)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Item not in trash", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+
+		err := Restore(ItemGame, game.Id, userId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Item of another user", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		_, game := makeGame(userId)
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+
+		err := Restore(ItemGame, game.Id, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+
+	t.Run("Platform name used again - conflict", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform := &platforms.Platform{Name: "test", ShortName: "tst"}
+		tests.PanicOnErr(platforms.CreatePlatform(platform, userId))
+		tests.PanicOnErr(platforms.DeletePlatform(platform.Id, 0, userId))
+		tests.PanicOnErr(platforms.CreatePlatform(&platforms.Platform{Name: "test", ShortName: "new"}, userId))
+
+		err := Restore(ItemPlatform, platform.Id, userId)
+
+		assert.ErrorIs(t, err, operations.Errors.DataAlreadyExistErr)
+	})
+}
+
+func TestPurgeTx(t *testing.T) {
+	t.Run("Only items deleted before the time purged", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform, game := makeGame(userId)
+		makePlaythrough(game.Id, userId)
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+		tests.PanicOnErr(platforms.DeletePlatform(platform.Id, 0, userId))
+		_, recent := makeGame(userId)
+		tests.PanicOnErr(games.DeleteGame(recent.Id, 0, userId))
+		_, err := getDatabase().Exec(`update games set deleted_at = $2 where id = $1`, game.Id, time.Now().AddDate(0, 0, -40))
+		tests.PanicOnErr(err)
+		_, err = getDatabase().Exec(`update platforms set deleted_at = $2 where id = $1`, platform.Id, time.Now().AddDate(0, 0, -40))
+		tests.PanicOnErr(err)
+
+		count, err := PurgeTx(getDatabase(), time.Now().AddDate(0, 0, -30))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		items, err := GetItems(userId)
+		assert.NoError(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, recent.Id, items[0].Id)
+	})
+
+	t.Run("Platform used by a game in the trash kept", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		platform, game := makeGame(userId)
+		tests.PanicOnErr(games.DeleteGame(game.Id, 0, userId))
+		tests.PanicOnErr(platforms.DeletePlatform(platform.Id, 0, userId))
+		_, err := getDatabase().Exec(`update platforms set deleted_at = $2 where id = $1`, platform.Id, time.Now().AddDate(0, 0, -40))
+		tests.PanicOnErr(err)
+
+		count, err := PurgeTx(getDatabase(), time.Now().AddDate(0, 0, -30))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
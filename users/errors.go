@@ -0,0 +1,7 @@
+package users
+
+import "errors"
+
+var (
+	LastIdentityErr = errors.New("the last identity linked to the user cannot be removed")
+)
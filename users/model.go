@@ -3,21 +3,34 @@ package users
 import (
 	"github.com/google/uuid"
 	"github.com/markbates/goth"
+	"time"
 )
 
 type User struct {
+	Id    uuid.UUID
+	Email string
+}
+
+func (u *User) SetId(id uuid.UUID) {
+	u.Id = id
+}
+
+// Identity is a single account at an external login provider linked to a [User].
+type Identity struct {
 	Id           uuid.UUID
+	UserId       uuid.UUID
 	ProviderId   string
 	ProviderName string
 	Email        string
+	CreatedAt    time.Time
 }
 
-func (u *User) SetId(id uuid.UUID) {
-	u.Id = id
+func (i *Identity) SetId(id uuid.UUID) {
+	i.Id = id
 }
 
-func NewFromProvider(providerUser *goth.User) *User {
-	return &User{
+func NewFromProvider(providerUser *goth.User) *Identity {
+	return &Identity{
 		ProviderId:   providerUser.UserID,
 		ProviderName: providerUser.Provider,
 		Email:        providerUser.Email,
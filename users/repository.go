@@ -1,21 +1,123 @@
 package users
 
 import (
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
 	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/utils"
 	"github.com/google/uuid"
 )
 
-// GetOrCreate sets the id of the provider User object.
-// If the User does not yet exist in the database, it will be created.
+// GetOrCreate resolves the [User] linked to the provider [Identity] and sets the ids in the Identity object.
+// If the Identity is not yet linked to any User, a new User will be created for it.
 //
-// If the user does exist, the email address will be updated to match the one provided from identity provider.
-func GetOrCreate(user *User) error {
-	query := `insert into users (provider_id, provider_name, email) values ($1, $2, $3) on conflict (provider_id, provider_name) do update set email = $3 returning id`
-	return operations.CreateRowWithId(getDatabase(), user, query, user.ProviderId, user.ProviderName, user.Email)
+// If the Identity does exist, its email address will be updated to match the one provided from identity provider.
+func GetOrCreate(identity *Identity) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		query := `update user_identities set email = $3 where provider_id = $1 and provider_name = $2 returning id, user_id, provider_id, provider_name, email, created_at`
+		existing, err := operations.QueryRow(tx, scanIdentity, query, identity.ProviderId, identity.ProviderName, identity.Email)
+		if err == nil {
+			*identity = *existing
+			return nil
+		}
+		if !errors.Is(err, operations.Errors.DataNotFoundErr) {
+			return err
+		}
+
+		user := &User{Email: identity.Email}
+		query = `insert into users (email) values ($1) returning id`
+		if err = operations.CreateRowWithId(tx, user, query, user.Email); err != nil {
+			return err
+		}
+
+		return createIdentity(tx, identity, user.Id)
+	})
 }
 
 // GetById returns all User data by the user id.
 func GetById(id uuid.UUID) (*User, error) {
-	query := `select id, provider_id, provider_name, email from users where id = $1`
+	query := `select id, email from users where id = $1`
 	return operations.QueryRow[User](getDatabase(), scanUser, query, id)
 }
+
+// DeleteUser removes the user with all of their data from the database.
+func DeleteUser(id uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		// games have to be removed before platforms, as platforms cannot be deleted while still in use
+		// playthroughs are removed with games, while sessions, identities and tokens are removed with the user
+		if err := operations.TryDelete(tx, `delete from games where user_id = $1`, id); err != nil {
+			return err
+		}
+		if err := operations.TryDelete(tx, `delete from platforms where user_id = $1`, id); err != nil {
+			return err
+		}
+		return operations.DeleteRow(tx, `delete from users where id = $1`, id)
+	})
+}
+
+// GetIdentities returns all identities linked to the user.
+func GetIdentities(userId uuid.UUID) ([]*Identity, error) {
+	query := `select id, user_id, provider_id, provider_name, email, created_at from user_identities where user_id = $1 order by created_at`
+	return operations.QueryRows(getDatabase(), scanIdentity, query, userId)
+}
+
+// LinkIdentity links an additional provider [Identity] to an existing user.
+// An Identity that is already linked to any user cannot be linked again.
+func LinkIdentity(identity *Identity, userId uuid.UUID) error {
+	return createIdentity(getDatabase(), identity, userId)
+}
+
+// UnlinkIdentity removes a single [Identity] from the user.
+// The last Identity of the user cannot be removed, as that would prevent the user from logging in.
+func UnlinkIdentity(id uuid.UUID, userId uuid.UUID) error {
+	return utils.RunInTransaction(func(tx gotabase.Connector) error {
+		// lock the user to prevent concurrent removals from unlinking all identities
+		lockQuery := `select id, email from users where id = $1 for update`
+		if _, err := operations.QueryRow(tx, scanUser, lockQuery, userId); err != nil {
+			return err
+		}
+
+		count, err := countIdentities(tx, userId)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			if _, err = getIdentity(tx, id, userId); err != nil {
+				return err
+			}
+			return LastIdentityErr
+		}
+
+		query := `delete from user_identities where id = $1 and user_id = $2`
+		return operations.DeleteRow(tx, query, id, userId)
+	})
+}
+
+func createIdentity(connector gotabase.Connector, identity *Identity, userId uuid.UUID) error {
+	query := `insert into user_identities (user_id, provider_id, provider_name, email) values ($1, $2, $3, $4) returning id, created_at`
+	return operations.CreateRowWithScan(connector, identity, func(row gotabase.Row, identity *Identity) error {
+		if err := row.Scan(&identity.Id, &identity.CreatedAt); err != nil {
+			return err
+		}
+		identity.UserId = userId
+		return nil
+	}, query, userId, identity.ProviderId, identity.ProviderName, identity.Email)
+}
+
+func getIdentity(connector gotabase.Connector, id uuid.UUID, userId uuid.UUID) (*Identity, error) {
+	query := `select id, user_id, provider_id, provider_name, email, created_at from user_identities where id = $1 and user_id = $2`
+	return operations.QueryRow(connector, scanIdentity, query, id, userId)
+}
+
+func countIdentities(connector gotabase.Connector, userId uuid.UUID) (int, error) {
+	query := `select count(1) from user_identities where user_id = $1`
+	row, err := connector.QueryRow(query, userId)
+	if err != nil {
+		return 0, operations.Errors.HandleError(err)
+	}
+	var count int
+	if err = row.Scan(&count); err != nil {
+		return 0, operations.Errors.HandleError(err)
+	}
+	return count, nil
+}
@@ -1,41 +1,165 @@
 package users
 
 import (
+	"github.com/KowalskiPiotr98/gotabase/operations"
 	"github.com/KowalskiPiotr98/ludivault/internal/tests"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
+func makeTestIdentity(providerId string) *Identity {
+	return &Identity{
+		Email:        "user@localhost",
+		ProviderId:   providerId,
+		ProviderName: "test",
+	}
+}
+
 func TestGet(t *testing.T) {
 	t.Run("new user created", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
-		user := &User{
-			Email:        "user@localhost",
-			ProviderId:   "localhost",
-			ProviderName: "test",
-		}
+		identity := makeTestIdentity("localhost")
 
-		err := GetOrCreate(user)
+		err := GetOrCreate(identity)
 
 		assert.NoError(t, err)
-		assert.NotEqual(t, uuid.Nil, user.Id)
+		assert.NotEqual(t, uuid.Nil, identity.Id)
+		assert.NotEqual(t, uuid.Nil, identity.UserId)
 	})
 
 	t.Run("existing user updated", func(t *testing.T) {
 		tests.GetDatabaseWithCleanup(t)
-		user := &User{
-			Email:        "user@localhost",
-			ProviderId:   "localhost",
-			ProviderName: "test",
-		}
-		tests.PanicOnErr(GetOrCreate(user))
+		identity := makeTestIdentity("localhost")
+		tests.PanicOnErr(GetOrCreate(identity))
+		userId := identity.UserId
+
+		identity = makeTestIdentity("localhost")
+		identity.Email = "user2@localhost"
+		err := GetOrCreate(identity)
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, identity.UserId)
+		assert.Equal(t, "user2@localhost", identity.Email)
+	})
+
+	t.Run("linked identity resolves to the same user", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		identity := makeTestIdentity("localhost")
+		tests.PanicOnErr(GetOrCreate(identity))
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("other"), identity.UserId))
+
+		linked := makeTestIdentity("other")
+		err := GetOrCreate(linked)
+
+		assert.NoError(t, err)
+		assert.Equal(t, identity.UserId, linked.UserId)
+	})
+}
+
+func TestLinkIdentity(t *testing.T) {
+	t.Run("identity linked", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		identity := makeTestIdentity("localhost")
+
+		err := LinkIdentity(identity, userId)
+
+		assert.NoError(t, err)
+		list, err := GetIdentities(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, identity.Id, list[0].Id)
+	})
+
+	t.Run("identity already linked to another user", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("localhost"), tests.MakeTestUserId(getDatabase())))
+
+		err := LinkIdentity(makeTestIdentity("localhost"), tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataAlreadyExistErr, err)
+	})
+}
+
+func TestUnlinkIdentity(t *testing.T) {
+	t.Run("identity unlinked", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		identity := makeTestIdentity("localhost")
+		tests.PanicOnErr(LinkIdentity(identity, userId))
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("other"), userId))
 
-		user.Email = "user2@localhost"
-		user.Id = uuid.Nil
-		err := GetOrCreate(user)
+		err := UnlinkIdentity(identity.Id, userId)
 
 		assert.NoError(t, err)
-		assert.NotEqual(t, uuid.Nil, user.Id)
+		list, err := GetIdentities(userId)
+		tests.PanicOnErr(err)
+		assert.Len(t, list, 1)
+		assert.NotEqual(t, identity.Id, list[0].Id)
+	})
+
+	t.Run("last identity cannot be unlinked", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		identity := makeTestIdentity("localhost")
+		tests.PanicOnErr(LinkIdentity(identity, userId))
+
+		err := UnlinkIdentity(identity.Id, userId)
+
+		assert.Equal(t, LastIdentityErr, err)
+	})
+
+	t.Run("user not authorised", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		identity := makeTestIdentity("localhost")
+		tests.PanicOnErr(LinkIdentity(identity, userId))
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("other"), userId))
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("third"), otherUserId))
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("fourth"), otherUserId))
+
+		err := UnlinkIdentity(identity.Id, otherUserId)
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Run("user deleted with all data", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		tests.PanicOnErr(LinkIdentity(makeTestIdentity("localhost"), userId))
+		platformId := tests.GetRandomUuid()
+		_, err := getDatabase().Exec(`insert into platforms (id, name, short_name, user_id) values ($1, 'aa', 'aa', $2)`, platformId, userId)
+		tests.PanicOnErr(err)
+		gameId := tests.GetRandomUuid()
+		_, err = getDatabase().Exec(`insert into games (id, title, platform_id, released, user_id) values ($1, 'game', $2, true, $3)`, gameId, platformId, userId)
+		tests.PanicOnErr(err)
+		_, err = getDatabase().Exec(`insert into playthroughs (game_id, start_date) values ($1, now())`, gameId)
+		tests.PanicOnErr(err)
+		otherUserId := tests.MakeTestUserId(getDatabase())
+
+		err = DeleteUser(userId)
+
+		assert.NoError(t, err)
+		_, err = GetById(userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+		var count int
+		row, err := getDatabase().QueryRow(`select (select count(1) from platforms) + (select count(1) from games) + (select count(1) from playthroughs)`)
+		tests.PanicOnErr(err)
+		tests.PanicOnErr(row.Scan(&count))
+		assert.Zero(t, count)
+		_, err = GetById(otherUserId)
+		assert.NoError(t, err)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+
+		err := DeleteUser(tests.GetRandomUuid())
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
 	})
 }
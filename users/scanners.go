@@ -4,8 +4,16 @@ import "github.com/KowalskiPiotr98/gotabase"
 
 func scanUser(row gotabase.Row) (*User, error) {
 	var user User
-	if err := row.Scan(&user.Id, &user.ProviderId, &user.ProviderName, &user.Email); err != nil {
+	if err := row.Scan(&user.Id, &user.Email); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func scanIdentity(row gotabase.Row) (*Identity, error) {
+	var identity Identity
+	if err := row.Scan(&identity.Id, &identity.UserId, &identity.ProviderId, &identity.ProviderName, &identity.Email, &identity.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
@@ -2,6 +2,10 @@ package utils
 
 import (
 	"database/sql"
+	"errors"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	log "github.com/sirupsen/logrus"
 	"time"
 )
 
@@ -15,3 +19,66 @@ func MakeNullTime(value *time.Time) sql.NullTime {
 		Time:  *value,
 	}
 }
+
+// RunInTransaction executes the action within a new database transaction.
+// The transaction is committed if the action succeeds and rolled back otherwise.
+func RunInTransaction(action func(tx gotabase.Connector) error) error {
+	return RunInTransactionWithDryRun(false, action)
+}
+
+// RunInTransactionWithDryRun works like RunInTransaction, but always rolls the transaction back when dryRun is set,
+// so that the action can report what it would change without changing anything.
+func RunInTransactionWithDryRun(dryRun bool, action func(tx gotabase.Connector) error) error {
+	tx, err := gotabase.BeginTransaction()
+	if err != nil {
+		return err
+	}
+
+	if err = action(tx); err != nil || dryRun {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Warnf("Failed to rollback transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RowsErr returns the error, which ended the iteration over the rows early, if any.
+// It must be checked once Next returns false, as the rows of the driver report such errors, even though gotabase.Rows does not expose them.
+func RowsErr(rows gotabase.Rows) error {
+	if withErr, ok := rows.(interface{ Err() error }); ok {
+		return operations.Errors.HandleError(withErr.Err())
+	}
+	return nil
+}
+
+// VersionMismatchErr is returned when an item was changed since the version expected by the caller was read.
+var VersionMismatchErr = errors.New("item was changed in the meantime")
+
+// CheckVersionMismatch turns DataNotFoundErr, returned by a change expecting the version provided, into VersionMismatchErr if the item still exists.
+// Changes not expecting any version, with the version set to 0, are left as they are.
+func CheckVersionMismatch(err error, version int, exists func() bool) error {
+	if version != 0 && errors.Is(err, operations.Errors.DataNotFoundErr) && exists() {
+		return VersionMismatchErr
+	}
+	return err
+}
+
+func scanVersion(row gotabase.Row) (*int, error) {
+	var version int
+	if err := row.Scan(&version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// UpdateVersionedRow runs the query, which must update a single row and return its new version.
+// The new version is returned, or DataNotFoundErr if no row was updated.
+func UpdateVersionedRow(connector gotabase.Connector, query string, args ...any) (int, error) {
+	version, err := operations.QueryRow(connector, scanVersion, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return *version, nil
+}
@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// pollInterval is how often the dispatcher checks for deliveries to send.
+	pollInterval = 5 * time.Second
+	// batchSize is the number of deliveries claimed at once.
+	batchSize = 20
+	// lease is how long claimed deliveries are hidden from other instances while they are being sent.
+	lease = time.Minute
+	// maxAttempts is the number of attempts after which a delivery is marked as failed.
+	maxAttempts = 6
+	// firstRetryDelay is the delay after the first failed attempt, doubled after each next one.
+	firstRetryDelay = 30 * time.Second
+	// maxErrorLength limits the length of errors saved with deliveries.
+	maxErrorLength = 1000
+
+	EventHeader     = "X-Ludivault-Event"
+	DeliveryHeader  = "X-Ludivault-Delivery"
+	SignatureHeader = "X-Ludivault-Signature"
+)
+
+// Dispatcher sends queued deliveries to webhooks.
+//
+// Deliveries are safe to dispatch on several instances of the application sharing the database,
+// as each instance claims the deliveries it sends.
+type Dispatcher struct {
+	// clock returns the current time, and can be replaced in tests.
+	clock  func() time.Time
+	client *http.Client
+}
+
+// NewDispatcher creates a [Dispatcher], using the clock to tell the current time.
+//
+// Webhooks are only sent to public addresses, and redirects are not followed,
+// so that they cannot be used to reach services on the internal network.
+func NewDispatcher(clock func() time.Time) *Dispatcher {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: checkAddress}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		// proxies are not used, as the address of the webhook could not be checked then
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &Dispatcher{clock: clock, client: client}
+}
+
+// checkAddress refuses connections to addresses, which are not public.
+// It is called after the host name is resolved, right before connecting, so names resolving to such addresses are refused as well.
+func checkAddress(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", AddressNotAllowedErr, host)
+	}
+	return nil
+}
+
+// Start sends deliveries in the background every few seconds, until the context is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			d.DispatchDue()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// DispatchDue sends all deliveries, which are due according to the clock.
+// Outcomes of the attempts are saved with the deliveries, failures to access the database are logged.
+func (d *Dispatcher) DispatchDue() {
+	for {
+		pending, err := claimPending(d.clock(), lease, batchSize)
+		if err != nil {
+			log.Warnf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		for _, delivery := range pending {
+			d.dispatch(delivery)
+			if err = recordAttempt(&delivery.Delivery); err != nil {
+				log.Warnf("Failed to record attempt of webhook delivery %s: %v", delivery.Id, err)
+			}
+		}
+
+		if len(pending) < batchSize {
+			return
+		}
+	}
+}
+
+// dispatch makes a single attempt to send the delivery, updating it with the outcome.
+func (d *Dispatcher) dispatch(delivery *pendingDelivery) {
+	delivery.Attempts++
+	delivery.LastAttemptAt = sql.NullTime{Time: d.clock(), Valid: true}
+	delivery.ResponseStatus = sql.NullInt32{}
+	delivery.Error = sql.NullString{}
+
+	status, err := d.send(delivery)
+	if status != 0 {
+		delivery.ResponseStatus = sql.NullInt32{Int32: int32(status), Valid: true}
+	}
+	if err == nil {
+		delivery.Status = DeliveryDelivered
+		return
+	}
+
+	log.Debugf("Webhook delivery %s failed: %v", delivery.Id, err)
+	delivery.Error = sql.NullString{String: truncateError(err.Error()), Valid: true}
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = DeliveryFailed
+		return
+	}
+	delivery.Status = DeliveryPending
+	delivery.NextAttemptAt = delivery.LastAttemptAt.Time.Add(backoff(delivery.Attempts))
+}
+
+// send posts the payload of the delivery to the webhook, returning the status of the response, if any was received.
+// Any response other than 2xx is an error.
+func (d *Dispatcher) send(delivery *pendingDelivery) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, delivery.url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Ludivault-Webhooks")
+	request.Header.Set(EventHeader, string(delivery.Event))
+	request.Header.Set(DeliveryHeader, delivery.Id.String())
+	request.Header.Set(SignatureHeader, Sign(delivery.Payload, delivery.secret))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// truncateError limits the length of the error saved with a delivery.
+// The error is only cut between characters, and invalid UTF-8 is replaced, as the database would reject it.
+func truncateError(message string) string {
+	message = strings.ToValidUTF8(message, "\uFFFD")
+	if len(message) <= maxErrorLength {
+		return message
+	}
+	split := maxErrorLength
+	for split > 0 && !utf8.RuneStart(message[split]) {
+		split--
+	}
+	return message[:split]
+}
+
+// Sign returns the signature of the payload sent in the [SignatureHeader]: sha256= followed by hex encoded HMAC-SHA256 of the payload, keyed with the secret.
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay before the next attempt, after the number of failed attempts as provided.
+func backoff(attempts int) time.Duration {
+	return firstRetryDelay << (attempts - 1)
+}
@@ -0,0 +1,188 @@
+package webhooks
+
+import (
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// testClock is a clock, which only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestSign(t *testing.T) {
+	// known HMAC-SHA256 test vector
+	signature := Sign([]byte("The quick brown fox jumps over the lazy dog"), "key")
+
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", signature)
+}
+
+func TestTruncateError(t *testing.T) {
+	t.Run("Short error kept", func(t *testing.T) {
+		assert.Equal(t, "connection refused", truncateError("connection refused"))
+	})
+
+	t.Run("Long error cut between characters", func(t *testing.T) {
+		message := strings.Repeat("a", maxErrorLength-1) + "ż" + "b"
+
+		truncated := truncateError(message)
+
+		assert.Equal(t, strings.Repeat("a", maxErrorLength-1), truncated)
+		assert.True(t, utf8.ValidString(truncated))
+	})
+
+	t.Run("Invalid UTF-8 replaced", func(t *testing.T) {
+		assert.True(t, utf8.ValidString(truncateError("bad \xff byte")))
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backoff(1))
+	assert.Equal(t, time.Minute, backoff(2))
+	assert.Equal(t, 8*time.Minute, backoff(5))
+}
+
+// newTestDispatcher creates a [Dispatcher], which can send webhooks to test servers on the loopback address.
+func newTestDispatcher(clock *testClock) *Dispatcher {
+	dispatcher := newTestDispatcher(clock)
+	dispatcher.client = &http.Client{Timeout: 10 * time.Second}
+	return dispatcher
+}
+
+func TestCheckAddress(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:80", "10.0.0.1:80", "192.168.1.1:443", "172.16.0.1:80", "169.254.169.254:80", "[fe80::1]:80", "0.0.0.0:80", "[::ffff:127.0.0.1]:80"} {
+		assert.ErrorIs(t, checkAddress("tcp", address, nil), AddressNotAllowedErr, address)
+	}
+	assert.NoError(t, checkAddress("tcp", "93.184.216.34:443", nil))
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("Signed delivery sent", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+		webhook := makeWebhook(server.URL, userId, EventGameCreated)
+		emit(userId)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+		newTestDispatcher(clock).DispatchDue()
+
+		delivery := getDelivery(webhook.Id)
+		assert.Equal(t, DeliveryDelivered, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, int32(http.StatusOK), delivery.ResponseStatus.Int32)
+		assert.NotNil(t, received)
+		assert.Equal(t, string(EventGameCreated), received.Header.Get(EventHeader))
+		assert.Equal(t, delivery.Id.String(), received.Header.Get(DeliveryHeader))
+		assert.Equal(t, Sign(body, webhook.Secret), received.Header.Get(SignatureHeader))
+	})
+
+	t.Run("Failed delivery retried later", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		webhook := makeWebhook(server.URL, userId, EventGameCreated)
+		emit(userId)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		dispatcher := newTestDispatcher(clock)
+
+		dispatcher.DispatchDue()
+		dispatcher.DispatchDue()
+
+		assert.Equal(t, 1, calls)
+		delivery := getDelivery(webhook.Id)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, int32(http.StatusInternalServerError), delivery.ResponseStatus.Int32)
+		assert.True(t, delivery.Error.Valid)
+		assert.True(t, clock.now.Add(backoff(1)).Equal(delivery.NextAttemptAt))
+
+		clock.now = clock.now.Add(backoff(1))
+		dispatcher.DispatchDue()
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Delivery failed after last attempt", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+		webhook := makeWebhook(server.URL, userId, EventGameCreated)
+		emit(userId)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		dispatcher := newTestDispatcher(clock)
+
+		for i := 0; i < maxAttempts; i++ {
+			dispatcher.DispatchDue()
+			clock.now = clock.now.Add(backoff(maxAttempts))
+		}
+
+		delivery := getDelivery(webhook.Id)
+		assert.Equal(t, DeliveryFailed, delivery.Status)
+		assert.Equal(t, maxAttempts, delivery.Attempts)
+	})
+
+	t.Run("Loopback address - not sent", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer server.Close()
+		webhook := makeWebhook(server.URL, userId, EventGameCreated)
+		emit(userId)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+		NewDispatcher(clock.Now).DispatchDue()
+
+		assert.Equal(t, 0, calls)
+		delivery := getDelivery(webhook.Id)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.False(t, delivery.ResponseStatus.Valid)
+		assert.Contains(t, delivery.Error.String, AddressNotAllowedErr.Error())
+	})
+
+	t.Run("Redirect - not followed", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusTemporaryRedirect)
+		}))
+		defer server.Close()
+		webhook := makeWebhook(server.URL, userId, EventGameCreated)
+		emit(userId)
+		clock := &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+		dispatcher := newTestDispatcher(clock)
+		dispatcher.client.CheckRedirect = NewDispatcher(clock.Now).client.CheckRedirect
+
+		dispatcher.DispatchDue()
+
+		delivery := getDelivery(webhook.Id)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, int32(http.StatusTemporaryRedirect), delivery.ResponseStatus.Int32)
+	})
+}
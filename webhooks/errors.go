@@ -0,0 +1,8 @@
+package webhooks
+
+import "errors"
+
+var (
+	// AddressNotAllowedErr is returned when a webhook points to a loopback, private, link-local or unspecified address.
+	AddressNotAllowedErr = errors.New("webhook address is not allowed")
+)
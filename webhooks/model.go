@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"database/sql"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventGameCreated          EventType = "game.created"
+	EventPlaythroughCompleted EventType = "playthrough.completed"
+	EventPlaythroughDropped   EventType = "playthrough.dropped"
+)
+
+// EventTypes lists all events, which webhooks can subscribe to.
+var EventTypes = []EventType{EventGameCreated, EventPlaythroughCompleted, EventPlaythroughDropped}
+
+// Webhook is a subscription of a user to events, which are delivered to the URL.
+type Webhook struct {
+	Id     uuid.UUID
+	Url    string
+	Secret string
+	Events []EventType
+	// CreatedAt is only read from the database and is ignored when writing webhooks.
+	CreatedAt time.Time
+}
+
+func (w *Webhook) SetId(id uuid.UUID) {
+	w.Id = id
+}
+
+type DeliveryStatus int8
+
+const (
+	DeliveryPending DeliveryStatus = iota
+	DeliveryDelivered
+	DeliveryFailed
+)
+
+// Delivery is a single event sent, or to be sent, to a [Webhook].
+type Delivery struct {
+	Id             uuid.UUID
+	WebhookId      uuid.UUID
+	Event          EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus sql.NullInt32
+	Error          sql.NullString
+	CreatedAt      time.Time
+}
+
+// Payload is the body of each delivery.
+type Payload struct {
+	Event      EventType `json:"event"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+// GameData describes the game in the payload of game events.
+type GameData struct {
+	Id                   uuid.UUID  `json:"id"`
+	PlatformId           uuid.UUID  `json:"platformId"`
+	Title                string     `json:"title"`
+	Owned                bool       `json:"owned"`
+	ReleaseDate          *time.Time `json:"releaseDate,omitempty"`
+	ReleaseDatePrecision int        `json:"releaseDatePrecision"`
+	Released             bool       `json:"released"`
+}
+
+// PlaythroughData describes the playthrough in the payload of playthrough events.
+type PlaythroughData struct {
+	Id        uuid.UUID  `json:"id"`
+	GameId    uuid.UUID  `json:"gameId"`
+	GameTitle string     `json:"gameTitle"`
+	StartDate time.Time  `json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+	Status    int        `json:"status"`
+	Runtime   *int       `json:"runtime,omitempty"`
+}
+
+func scanWebhook(row gotabase.Row) (*Webhook, error) {
+	var webhook Webhook
+	var events []string
+	if err := row.Scan(&webhook.Id, &webhook.Url, &webhook.Secret, pq.Array(&events), &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.Events = make([]EventType, len(events))
+	for i, event := range events {
+		webhook.Events[i] = EventType(event)
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row gotabase.Row) (*Delivery, error) {
+	var delivery Delivery
+	if err := row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&delivery.NextAttemptAt, &delivery.LastAttemptAt, &delivery.ResponseStatus, &delivery.Error, &delivery.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
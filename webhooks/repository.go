@@ -0,0 +1,104 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"github.com/KowalskiPiotr98/gotabase"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	selectWebhooks   = `select id, url, secret, event_types, created_at from webhooks`
+	selectDeliveries = `select d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_attempt_at, d.response_status, d.error, d.created_at from webhook_deliveries d`
+)
+
+// GetWebhooks returns all webhooks of the user.
+func GetWebhooks(userId uuid.UUID) ([]*Webhook, error) {
+	query := selectWebhooks + ` where user_id = $1 order by created_at`
+	return operations.QueryRows(getDatabase(), scanWebhook, query, userId)
+}
+
+// GetWebhook returns a single webhook selected by id.
+func GetWebhook(id uuid.UUID, userId uuid.UUID) (*Webhook, error) {
+	query := selectWebhooks + ` where id = $1 and user_id = $2`
+	return operations.QueryRow(getDatabase(), scanWebhook, query, id, userId)
+}
+
+// CreateWebhook creates a new webhook.
+func CreateWebhook(webhook *Webhook, userId uuid.UUID) error {
+	query := `insert into webhooks (url, secret, event_types, user_id) values ($1, $2, $3, $4) returning id, created_at`
+	return operations.CreateRowWithScan(getDatabase(), webhook, func(row gotabase.Row, webhook *Webhook) error {
+		return row.Scan(&webhook.Id, &webhook.CreatedAt)
+	}, query, webhook.Url, webhook.Secret, pq.Array(webhook.Events), userId)
+}
+
+// UpdateWebhook changes the URL, secret and events of a single webhook.
+// The secret is left unchanged if empty. Deliveries which are still pending are sent to the new URL.
+func UpdateWebhook(webhook *Webhook, userId uuid.UUID) error {
+	query := `update webhooks set url = $2, secret = coalesce(nullif($3, ''), secret), event_types = $4 where id = $1 and user_id = $5`
+	return operations.UpdateRow(getDatabase(), query, webhook.Id, webhook.Url, webhook.Secret, pq.Array(webhook.Events), userId)
+}
+
+// DeleteWebhook deletes a single webhook, along with all of its deliveries.
+func DeleteWebhook(id uuid.UUID, userId uuid.UUID) error {
+	query := `delete from webhooks where id = $1 and user_id = $2`
+	return operations.DeleteRow(getDatabase(), query, id, userId)
+}
+
+// GetDeliveries returns deliveries of the webhook, from the most recent ones, with offset and limit used for pagination.
+func GetDeliveries(webhookId uuid.UUID, offset int, limit int, userId uuid.UUID) ([]*Delivery, error) {
+	if _, err := GetWebhook(webhookId, userId); err != nil {
+		return nil, err
+	}
+
+	query := selectDeliveries + ` where d.webhook_id = $1 order by d.created_at desc, d.id offset $2 limit $3`
+	return operations.QueryRows(getDatabase(), scanDelivery, query, webhookId, offset, limit)
+}
+
+// EmitTx queues deliveries of the event to all webhooks of the user subscribed to it.
+// The deliveries are only sent once the transaction is committed, so events of changes that are rolled back are never sent.
+func EmitTx(connector gotabase.Connector, event EventType, data any, userId uuid.UUID) error {
+	payload, err := json.Marshal(&Payload{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	query := `insert into webhook_deliveries (webhook_id, event_type, payload)
+		select id, $2, $3 from webhooks where user_id = $1 and $2 = any(event_types)`
+	_, err = connector.Exec(query, userId, event, payload)
+	return operations.Errors.HandleError(err)
+}
+
+// pendingDelivery is a [Delivery] claimed for sending, along with its webhook.
+type pendingDelivery struct {
+	Delivery
+	url    string
+	secret string
+}
+
+// claimPending returns up to limit deliveries, which are due as of now, and postpones their next attempt by the lease.
+// This way other instances of the application do not send the same deliveries, unless the lease expires before they are sent.
+func claimPending(now time.Time, lease time.Duration, limit int) ([]*pendingDelivery, error) {
+	query := `update webhook_deliveries d set next_attempt_at = $2
+		from webhooks w
+		where w.id = d.webhook_id and d.id in (
+			select id from webhook_deliveries where status = 0 and next_attempt_at <= $1 order by next_attempt_at limit $3 for update skip locked
+		)
+		returning d.id, d.webhook_id, d.event_type, d.payload, d.attempts, w.url, w.secret`
+	return operations.QueryRows(getDatabase(), func(row gotabase.Row) (*pendingDelivery, error) {
+		var pending pendingDelivery
+		if err := row.Scan(&pending.Id, &pending.WebhookId, &pending.Event, &pending.Payload, &pending.Attempts, &pending.url, &pending.secret); err != nil {
+			return nil, err
+		}
+		return &pending, nil
+	}, query, now, now.Add(lease), limit)
+}
+
+// recordAttempt saves the outcome of an attempt to send the delivery.
+func recordAttempt(delivery *Delivery) error {
+	query := `update webhook_deliveries set status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5, response_status = $6, error = $7 where id = $1`
+	return operations.UpdateRow(getDatabase(), query, delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastAttemptAt, delivery.ResponseStatus, delivery.Error)
+}
@@ -0,0 +1,154 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"github.com/KowalskiPiotr98/gotabase/operations"
+	"github.com/KowalskiPiotr98/ludivault/internal/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func makeWebhook(url string, userId uuid.UUID, events ...EventType) *Webhook {
+	webhook := &Webhook{Url: url, Secret: "test-secret-0123456789", Events: events}
+	tests.PanicOnErr(CreateWebhook(webhook, userId))
+	return webhook
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("Webhook created", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+
+		webhook := makeWebhook("http://localhost/hook", userId, EventGameCreated, EventPlaythroughDropped)
+
+		assert.NotEqual(t, uuid.Nil, webhook.Id)
+		fromDb, err := GetWebhook(webhook.Id, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.Url, fromDb.Url)
+		assert.Equal(t, webhook.Secret, fromDb.Secret)
+		assert.Equal(t, []EventType{EventGameCreated, EventPlaythroughDropped}, fromDb.Events)
+	})
+}
+
+func TestGetWebhooks(t *testing.T) {
+	t.Run("Only webhooks of the user returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		otherUserId := tests.MakeTestUserId(getDatabase())
+		makeWebhook("http://localhost/one", userId, EventGameCreated)
+		makeWebhook("http://localhost/two", otherUserId, EventGameCreated)
+
+		list, err := GetWebhooks(userId)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "http://localhost/one", list[0].Url)
+	})
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	t.Run("Empty secret left unchanged", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := makeWebhook("http://localhost/hook", userId, EventGameCreated)
+
+		err := UpdateWebhook(&Webhook{Id: webhook.Id, Url: "http://localhost/new", Events: []EventType{EventPlaythroughCompleted}}, userId)
+
+		assert.NoError(t, err)
+		fromDb, err := GetWebhook(webhook.Id, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost/new", fromDb.Url)
+		assert.Equal(t, webhook.Secret, fromDb.Secret)
+		assert.Equal(t, []EventType{EventPlaythroughCompleted}, fromDb.Events)
+	})
+
+	t.Run("Webhook of another user not updated", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := makeWebhook("http://localhost/hook", userId, EventGameCreated)
+
+		webhook.Url = "http://localhost/new"
+		err := UpdateWebhook(webhook, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	t.Run("Webhook deleted", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := makeWebhook("http://localhost/hook", userId, EventGameCreated)
+
+		err := DeleteWebhook(webhook.Id, userId)
+
+		assert.NoError(t, err)
+		_, err = GetWebhook(webhook.Id, userId)
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func TestEmitTx(t *testing.T) {
+	t.Run("Deliveries queued for subscribed webhooks only", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		subscribed := makeWebhook("http://localhost/one", userId, EventGameCreated)
+		notSubscribed := makeWebhook("http://localhost/two", userId, EventPlaythroughCompleted)
+		otherUser := makeWebhook("http://localhost/three", tests.MakeTestUserId(getDatabase()), EventGameCreated)
+
+		err := EmitTx(getDatabase(), EventGameCreated, &GameData{Title: "test"}, userId)
+
+		assert.NoError(t, err)
+		deliveries, err := GetDeliveries(subscribed.Id, 0, 10, userId)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, EventGameCreated, deliveries[0].Event)
+		assert.Equal(t, DeliveryPending, deliveries[0].Status)
+		var payload struct {
+			Event EventType `json:"event"`
+			Data  GameData  `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+		assert.Equal(t, EventGameCreated, payload.Event)
+		assert.Equal(t, "test", payload.Data.Title)
+		deliveries, err = GetDeliveries(notSubscribed.Id, 0, 10, userId)
+		assert.NoError(t, err)
+		assert.Empty(t, deliveries)
+		assert.Equal(t, 0, countDeliveries(otherUser.Id))
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	t.Run("Deliveries of another user not returned", func(t *testing.T) {
+		tests.GetDatabaseWithCleanup(t)
+		userId := tests.MakeTestUserId(getDatabase())
+		webhook := makeWebhook("http://localhost/hook", userId, EventGameCreated)
+
+		_, err := GetDeliveries(webhook.Id, 0, 10, tests.MakeTestUserId(getDatabase()))
+
+		assert.Equal(t, operations.Errors.DataNotFoundErr, err)
+	})
+}
+
+func countDeliveries(webhookId uuid.UUID) int {
+	row, err := getDatabase().QueryRow(`select count(1) from webhook_deliveries where webhook_id = $1`, webhookId)
+	tests.PanicOnErr(err)
+	var count int
+	tests.PanicOnErr(row.Scan(&count))
+	return count
+}
+
+func getDelivery(webhookId uuid.UUID) *Delivery {
+	delivery, err := operations.QueryRow(getDatabase(), scanDelivery, selectDeliveries+` where d.webhook_id = $1`, webhookId)
+	tests.PanicOnErr(err)
+	return delivery
+}
+
+func emit(userId uuid.UUID) {
+	tests.PanicOnErr(EmitTx(getDatabase(), EventGameCreated, &GameData{Title: "test"}, userId))
+	// deliveries are due right away, make sure the test clock is past that
+	_, err := getDatabase().Exec(`update webhook_deliveries set next_attempt_at = $1`, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	tests.PanicOnErr(err)
+}